@@ -1,27 +1,61 @@
 package mojo
 
 import (
+	"strconv"
 	"strings"
 )
 
 // Assemble assembles the given objects back into arguments.
+//
+// Consecutive counting flags with the same name, including multiple flags
+// made up of only that flag (e.g. -v -vv), are assembled in a compact form,
+// which is -vvv for short flags and --verbose=3 for long flags. Note that the
+// compact form of short flags can only be parsed if AllowMutipleFlags in Config
+// is set.
 func (objs Objects) Assemble() ([]string, error) {
 	return assemble(objs)
 }
 
 // assemble assembles arguments from the given objects.
 func assemble(objs []Object) ([]string, error) {
-	var args []string
+	var (
+		args []string
+
+		// Whether the double dash was passed, and whether it has been
+		// put back.
+		passedDoubleDash bool
+		doubleDash       bool
+	)
 
 	for len(objs) > 0 {
 		switch objs[0].(type) {
 		case CommandObject:
-			args = append(args, objs[0].(CommandObject).Name)
+			cmdObj := objs[0].(CommandObject)
+			if cmdObj.DoubleDash {
+				passedDoubleDash = true
+			}
+
+			args = append(args, cmdObj.Name)
 			objs = objs[1:]
 		case ArgumentObject:
-			args = append(args, objs[0].(ArgumentObject).Value)
+			argObj := objs[0].(ArgumentObject)
+
+			// Put the double dash back before the first argument
+			// that came after it.
+			if argObj.AfterDoubleDash && !doubleDash {
+				args = append(args, "--")
+				doubleDash = true
+			}
+
+			args = append(args, argObj.Value)
 			objs = objs[1:]
 		case FlagObject:
+			// Skip the flag if it wasn't parsed from the arguments.
+			if objs[0].(FlagObject).Source != SourceArgument {
+				objs = objs[1:]
+				continue
+			}
+
 			newArgs, n, err := assembleFlag(objs)
 			if err != nil {
 				return nil, err
@@ -33,6 +67,11 @@ func assemble(objs []Object) ([]string, error) {
 		}
 	}
 
+	// Put the double dash back at the end if no arguments came after it.
+	if passedDoubleDash && !doubleDash {
+		args = append(args, "--")
+	}
+
 	return args, nil
 }
 
@@ -48,11 +87,22 @@ func assembleFlag(objs []Object) ([]string, int, error) {
 
 	// Panic if the first object is not a flag.
 	obj := objs[0].(FlagObject)
+
+	// Assemble consecutive counting flags together.
+	if obj.Counter {
+		if count := countFlags(objs); count > 1 {
+			return []string{compactCountFlag(obj.Name, count)}, count, nil
+		}
+	}
 	objs = objs[1:]
 
 	// Extract the possible name.
 	var name strings.Builder
-	name.WriteString(obj.Name)
+	if obj.Abbreviation != "" {
+		name.WriteString(obj.Abbreviation)
+	} else {
+		name.WriteString(obj.Name)
+	}
 
 	// If the flag is a multiple flag, then add all the names together.
 	if obj.MultipleFlagsStart {
@@ -88,20 +138,73 @@ func assembleFlag(objs []Object) ([]string, int, error) {
 		}
 	}
 
-	// If the flag isn't a bool flag and it is a combined flag, append the
-	// value to the name.
-	if !obj.Bool && obj.CombinedFlagValues {
+	// If the flag isn't a bool flag and it is a combined or attached flag,
+	// append the value to the name. Flags with optional values always have
+	// their values attached, unless they were passed without values.
+	optional := obj.OptionalValue && !obj.ImplicitValue && !obj.CombinedFlagValues && !obj.AttachedFlagValue
+	switch {
+	case obj.Bool || obj.ImplicitValue:
+	case obj.CombinedFlagValues || optional && strings.HasPrefix(obj.Name, "--"):
 		name.WriteString("=" + obj.Value)
+	case obj.AttachedFlagValue || optional:
+		name.WriteString(obj.Value)
 	}
 
 	// Append the name to the arguments.
 	args = append(args, name.String())
 
-	// If the flag isn't a bool flag and also isn't a combined flag, append
-	// the value to the arguments.
-	if !obj.Bool && !obj.CombinedFlagValues {
+	// If the flag isn't a bool flag and also isn't a combined or attached
+	// flag, append the value to the arguments.
+	if !obj.Bool && !obj.CombinedFlagValues && !obj.AttachedFlagValue && !obj.OptionalValue {
 		args = append(args, obj.Value)
 	}
 
 	return args, n, nil
 }
+
+// countFlags returns the number of consecutive counting flags at the start of
+// the given objects which can be assembled together.
+//
+// Multiple flags (e.g. -vv) are only counted if all of them are the same
+// counting flag, since they can't be split.
+func countFlags(objs []Object) int {
+	name := objs[0].(FlagObject).Name
+	canCount := func(i int) bool {
+		flagObj, ok := objs[i].(FlagObject)
+		return ok && flagObj.Counter && flagObj.Bool && !flagObj.Negated &&
+			flagObj.Abbreviation == "" && flagObj.Source == SourceArgument &&
+			flagObj.Name == name
+	}
+
+	var count int
+	for count < len(objs) && canCount(count) {
+		flagObj := objs[count].(FlagObject)
+		if flagObj.MultipleFlagsEnd {
+			break
+		}
+		if !flagObj.MultipleFlagsStart {
+			count++
+			continue
+		}
+
+		// Find the end of the multiple flags.
+		end := count + 1
+		for end < len(objs) && canCount(end) && !objs[end].(FlagObject).MultipleFlagsEnd {
+			end++
+		}
+		if end == len(objs) || !canCount(end) {
+			break
+		}
+		count = end + 1
+	}
+	return count
+}
+
+// compactCountFlag returns the compact form of the counting flag with the
+// given name passed the given number of times (e.g. -vvv or --verbose=3).
+func compactCountFlag(name string, count int) string {
+	if !strings.HasPrefix(name, "--") && len(name) == 2 {
+		return "-" + strings.Repeat(name[1:], count)
+	}
+	return name + "=" + strconv.Itoa(count)
+}
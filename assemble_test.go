@@ -129,6 +129,137 @@ func TestObjects_Assemble(t *testing.T) {
 				args: []string{"tldr", "add", "--level", "5", "nmap"},
 			},
 		},
+		{
+			name: "DoubleDash",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "-v", Bool: true},
+					mojo.ArgumentObject{Value: "nmap"},
+					mojo.ArgumentObject{Value: "-l", AfterDoubleDash: true},
+					mojo.ArgumentObject{Value: "--", AfterDoubleDash: true},
+				},
+			},
+			want: rets{
+				args: []string{"tldr", "-v", "nmap", "--", "-l", "--"},
+			},
+		},
+		{
+			name: "DefaultFlag",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--level", Value: "1", Canonical: "--level", Source: mojo.SourceDefault},
+					mojo.ArgumentObject{Value: "nmap"},
+				},
+			},
+			want: rets{
+				args: []string{"tldr", "nmap"},
+			},
+		},
+		{
+			name: "NegatedFlag",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--no-color", Canonical: "--color", Bool: true, Negated: true},
+				},
+			},
+			want: rets{
+				args: []string{"tldr", "--no-color"},
+			},
+		},
+		{
+			name: "CountingFlags",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "-v", Canonical: "--verbose", Bool: true, Counter: true},
+					mojo.FlagObject{Name: "-v", Canonical: "--verbose", Bool: true, Counter: true},
+					mojo.FlagObject{Name: "--verbose", Canonical: "--verbose", Bool: true, Counter: true},
+					mojo.FlagObject{Name: "--verbose", Canonical: "--verbose", Bool: true, Counter: true},
+					mojo.ArgumentObject{Value: "nmap"},
+				},
+			},
+			want: rets{
+				args: []string{"tldr", "-vv", "--verbose=2", "nmap"},
+			},
+		},
+		{
+			name: "OptionalValueFlags",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "ls"},
+					mojo.FlagObject{Name: "--color", Value: "always", OptionalValue: true, ImplicitValue: true},
+					mojo.ArgumentObject{Value: "file.txt"},
+					mojo.FlagObject{Name: "-c", Value: "never", OptionalValue: true},
+					mojo.FlagObject{Name: "--color", Value: "auto", OptionalValue: true},
+				},
+			},
+			want: rets{
+				args: []string{"ls", "--color", "file.txt", "-cnever", "--color=auto"},
+			},
+		},
+		{
+			name: "AttachedFlagValues",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "make"},
+					mojo.FlagObject{Name: "-k", Bool: true, MultipleFlagsStart: true},
+					mojo.FlagObject{Name: "-s", Bool: true},
+					mojo.FlagObject{Name: "-j", Value: "8", MultipleFlagsEnd: true, AttachedFlagValue: true},
+					mojo.FlagObject{Name: "-O", Value: "2", AttachedFlagValue: true},
+				},
+			},
+			want: rets{
+				args: []string{"make", "-ksj8", "-O2"},
+			},
+		},
+		{
+			name: "FlagAbbreviations",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tool"},
+					mojo.FlagObject{Name: "--verbose", Abbreviation: "--verb", Bool: true, Counter: true},
+					mojo.FlagObject{Name: "--verbose", Bool: true, Counter: true},
+					mojo.FlagObject{Name: "--output", Value: "bin", Abbreviation: "--out", CombinedFlagValues: true},
+					mojo.FlagObject{Name: "--output", Value: "dist", Abbreviation: "--o"},
+				},
+			},
+			want: rets{
+				args: []string{"tool", "--verb", "--verbose", "--out=bin", "--o", "dist"},
+			},
+		},
+		{
+			name: "CountingMultipleFlags",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "-v", Canonical: "-v", Bool: true, Counter: true},
+					mojo.FlagObject{Name: "-v", Canonical: "-v", Bool: true, Counter: true},
+					mojo.FlagObject{Name: "-v", Canonical: "-v", Bool: true, Counter: true, MultipleFlagsStart: true},
+					mojo.FlagObject{Name: "-v", Canonical: "-v", Bool: true, Counter: true, MultipleFlagsEnd: true},
+					mojo.FlagObject{Name: "-a", Canonical: "-a", Bool: true, MultipleFlagsStart: true},
+					mojo.FlagObject{Name: "-v", Canonical: "-v", Bool: true, Counter: true, MultipleFlagsEnd: true},
+					mojo.FlagObject{Name: "-v", Canonical: "-v", Bool: true, Counter: true},
+				},
+			},
+			want: rets{
+				args: []string{"tldr", "-vvvv", "-av", "-v"},
+			},
+		},
+		{
+			name: "DoubleDashWithoutArguments",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tool", DoubleDash: true},
+					mojo.FlagObject{Name: "-v", Bool: true},
+				},
+			},
+			want: rets{
+				args: []string{"tool", "-v", "--"},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -144,3 +275,69 @@ func TestObjects_Assemble(t *testing.T) {
 		})
 	}
 }
+
+func TestParse_Assemble(t *testing.T) {
+	type args struct {
+		conf mojo.Config
+		args []string
+	}
+
+	type rets struct {
+		args []string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "DoubleDash",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tool",
+					},
+				},
+				args: []string{"tool", "--"},
+			},
+			want: rets{
+				args: []string{"tool", "--"},
+			},
+		},
+		{
+			name: "DoubleDashWithArguments",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tool",
+						Commands: []mojo.CommandConfig{
+							{Name: "sub"},
+						},
+					},
+				},
+				args: []string{"tool", "sub", "-v", "--", "-x", "sub"},
+			},
+			want: rets{
+				args: []string{"tool", "sub", "-v", "--", "-x", "sub"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objs, err := mojo.Parse(test.args.conf, test.args.args)
+			if err != nil {
+				t.Fatalf("want err %v, got err %v", nil, err)
+			}
+
+			var got rets
+			got.args, err = objs.Assemble()
+			if err != nil {
+				t.Fatalf("want err %v, got err %v", nil, err)
+			}
+			if !reflect.DeepEqual(got.args, test.want.args) {
+				t.Errorf("want args %v, got args %v", test.want.args, got.args)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package mojo
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Completion represents a candidate for completing an argument.
+type Completion struct {
+	Value       string
+	Description string
+	Kind        CompletionKind
+}
+
+// CompletionKind represents what kind of argument is being completed.
+type CompletionKind int
+
+// Possible completion kinds.
+const (
+	// CompletionCommand indicates that the candidate is the name of a
+	// subcommand.
+	CompletionCommand CompletionKind = iota
+
+	// CompletionFlag indicates that the candidate is the name or alias of
+	// a flag (e.g. --level), or multiple flags (e.g. -al).
+	CompletionFlag
+
+	// CompletionFlagValue indicates that the candidate is the value of a
+	// flag, which might be combined with the flag (e.g. --level=3).
+	CompletionFlagValue
+
+	// CompletionArgument indicates that the candidate is an argument.
+	CompletionArgument
+)
+
+// String returns the name of the completion kind.
+func (k CompletionKind) String() string {
+	switch k {
+	case CompletionCommand:
+		return "command"
+	case CompletionFlag:
+		return "flag"
+	case CompletionFlagValue:
+		return "flag value"
+	case CompletionArgument:
+		return "argument"
+	}
+	return "unknown"
+}
+
+// CompleteFunc returns the candidates for completing the given partial value
+// (e.g. the names of git branches).
+//
+// The candidates that don't start with the given value are left out, and Kind
+// is set based on what is being completed, so neither has to be handled.
+type CompleteFunc func(cur string) []Completion
+
+// CompleteValues returns a completion function which returns the given values
+// as candidates.
+func CompleteValues(values ...string) CompleteFunc {
+	return func(cur string) []Completion {
+		var completions []Completion
+		for _, value := range values {
+			completions = append(completions, Completion{Value: value})
+		}
+		return completions
+	}
+}
+
+// CompleteFiles returns a completion function which returns the files in the
+// directory of the partial value as candidates.
+//
+// If extensions (e.g. .json) are given, only files with one of them are
+// returned. Directories are always returned, with a trailing slash.
+func CompleteFiles(exts ...string) CompleteFunc {
+	return func(cur string) []Completion {
+		dir := cur[:strings.LastIndex(cur, "/")+1]
+
+		readDir := dir
+		if readDir == "" {
+			readDir = "."
+		}
+		infos, err := ioutil.ReadDir(readDir)
+		if err != nil {
+			return nil
+		}
+
+		var completions []Completion
+		for _, info := range infos {
+			if info.IsDir() {
+				completions = append(completions, Completion{Value: dir + info.Name() + "/"})
+				continue
+			}
+			if len(exts) > 0 && !hasExt(info.Name(), exts) {
+				continue
+			}
+			completions = append(completions, Completion{Value: dir + info.Name()})
+		}
+		return completions
+	}
+}
+
+// hasExt returns whether the given file name has one of the given extensions,
+// ignoring case.
+func hasExt(name string, exts []string) bool {
+	for _, ext := range exts {
+		if strings.EqualFold(filepath.Ext(name), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleComplete handles the hidden __complete command called by completion
+// scripts, if the given arguments call it (e.g. tldr __complete add --lev).
+//
+// The candidates for completing the last argument are written to w, one per
+// line and followed by a tab and their description if they have one. It
+// returns whether the command was handled, in which case the program should
+// exit without parsing the arguments.
+//
+// Check BashCompletion for more information.
+func HandleComplete(conf Config, args []string, w io.Writer) (bool, error) {
+	if len(args) < 2 || args[1] != completeCommand {
+		return false, nil
+	}
+
+	args = append([]string{args[0]}, args[2:]...)
+	i := len(args) - 1
+	if i < 1 {
+		i = 1
+	}
+
+	completions, err := Complete(conf, args, i)
+	if err != nil {
+		return true, err
+	}
+	for _, completion := range completions {
+		line := completion.Value
+		if completion.Description != "" {
+			line += "\t" + completion.Description
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// Complete returns the candidates for completing the argument at the given
+// index, using the given configuration.
+//
+// The first argument given should be the name of the root command (e.g. git).
+// The argument at the given index might be partially typed (e.g. --lev), and
+// the index can be the number of arguments to complete a new argument. The
+// arguments after the given index are ignored.
+func Complete(conf Config, args []string, i int) ([]Completion, error) {
+	if i < 1 || i > len(args) {
+		panic("runtime error: index out of bounds")
+	}
+
+	// Ensure there is an argument to complete.
+	if i == len(args) {
+		args = append(args[:i:i], "")
+	}
+	args = args[:i+1]
+	cur := args[i]
+
+	var (
+		commands   = []string{args[0]}
+		doubleDash bool
+
+		// n is the number of arguments passed to the current command.
+		n int
+	)
+
+	// Go through the arguments before the one being completed.
+	for j := 1; j < i; {
+		arg := args[j]
+
+		if doubleDash || !isFlag(conf, commands, arg) {
+			if cmd, ok := configCommand(conf, commands, arg); ok && !doubleDash {
+				commands = append(commands, cmd.Name)
+				n = 0
+			} else {
+				n++
+			}
+			j++
+			continue
+		}
+
+		if arg == "--" && !conf.DisallowDoubleDash {
+			doubleDash = true
+			j++
+			continue
+		}
+
+		// The argument being completed is included, since it might be
+		// the value of the flag.
+		flagObjs, width, err := parseFlag(conf, commands, args[j:])
+		if err != nil {
+			return nil, err
+		}
+		if j+width > i {
+			flagObj := flagObjs[len(flagObjs)-1]
+			return completeFlagValue(conf, commands, flagObj.Name, "", cur), nil
+		}
+		j += width
+	}
+
+	// Arguments after the double dash are never commands or flags.
+	if doubleDash {
+		return completeArgument(conf, commands, n, cur), nil
+	}
+
+	if !strings.HasPrefix(cur, "-") {
+		completions := completeSubcommand(conf, commands, cur)
+		return append(completions, completeArgument(conf, commands, n, cur)...), nil
+	}
+
+	// Check for combined flag value (i.e. --flag=value).
+	if k := strings.Index(cur, "="); !conf.DisallowCombinedFlagValues && k != -1 {
+		return completeFlagValue(conf, commands, cur[:k], cur[:k+1], cur[k+1:]), nil
+	}
+
+	// Check for multiple flags (e.g. -al).
+	if conf.AllowMutipleFlags && !strings.HasPrefix(cur, "--") && len(cur) > 2 {
+		return completeMultipleFlags(conf, commands, cur), nil
+	}
+
+	return completeFlag(conf, commands, cur), nil
+}
+
+// completeSubcommand returns the candidates for completing the given partial
+// subcommand of the command at the top of the given command stack.
+func completeSubcommand(conf Config, commands []string, cur string) []Completion {
+	var completions []Completion
+	for _, subcmd := range configCommands(conf, commands)[0].Commands {
+		if !strings.HasPrefix(subcmd.Name, cur) {
+			continue
+		}
+		completions = append(completions, Completion{
+			Value:       subcmd.Name,
+			Description: strings.SplitN(subcmd.Description, "\n", 2)[0],
+			Kind:        CompletionCommand,
+		})
+	}
+	return completions
+}
+
+// completeArgument returns the candidates for completing the given partial
+// argument of the command at the top of the given command stack, with the
+// given number of arguments before it.
+func completeArgument(conf Config, commands []string, n int, cur string) []Completion {
+	cmd := configCommands(conf, commands)[0]
+
+	complete := cmd.Complete
+	if arg, ok := cmd.argument(n); ok && arg.Complete != nil {
+		complete = arg.Complete
+	}
+	if complete == nil {
+		return nil
+	}
+	return filterCompletions(complete(cur), "", cur, CompletionArgument)
+}
+
+// completeFlag returns the candidates for completing the given partial flag
+// name in the command at the top of the given command stack.
+func completeFlag(conf Config, commands []string, cur string) []Completion {
+	var completions []Completion
+	for _, flag := range visibleFlags(conf, commands) {
+		names := append([]string{flag.Name}, flag.Aliases...)
+		for _, name := range append(names, flag.negatedNames()...) {
+			if !strings.HasPrefix(name, cur) {
+				continue
+			}
+			completions = append(completions, Completion{
+				Value:       name,
+				Description: flag.Description,
+				Kind:        CompletionFlag,
+			})
+		}
+	}
+	return completions
+}
+
+// completeMultipleFlags returns the candidates for completing the given
+// multiple flags (e.g. -al) in the command at the top of the given command
+// stack, which are the given flags followed by another short flag.
+//
+// All the given flags must be bool flags, since only the last flag can have a
+// value.
+func completeMultipleFlags(conf Config, commands []string, cur string) []Completion {
+	for _, c := range cur[1:] {
+		if _, err := newBoolFlag(conf, commands, "-"+string(c)); err != nil {
+			return nil
+		}
+	}
+
+	var completions []Completion
+	for _, flag := range visibleFlags(conf, commands) {
+		for _, name := range append([]string{flag.Name}, flag.Aliases...) {
+			if len(name) != 2 || strings.HasPrefix(name, "--") || strings.Contains(cur, name[1:]) {
+				continue
+			}
+			completions = append(completions, Completion{
+				Value:       cur + name[1:],
+				Description: flag.Description,
+				Kind:        CompletionFlag,
+			})
+		}
+	}
+	return completions
+}
+
+// completeFlagValue returns the candidates for completing the given partial
+// value of the flag with the given name in the command at the top of the given
+// command stack.
+//
+// The given prefix is prepended to the values of the candidates (e.g. --level=
+// if the value is combined with the flag).
+func completeFlagValue(conf Config, commands []string, name string, prefix string, cur string) []Completion {
+	flag, ok := configFlag(conf, commands, name)
+	if !ok {
+		return nil
+	}
+
+	if flag.Complete != nil {
+		return filterCompletions(flag.Complete(cur), prefix, cur, CompletionFlagValue)
+	}
+	if len(flag.Choices) > 0 {
+		return filterCompletions(CompleteValues(flag.Choices...)(cur), prefix, cur, CompletionFlagValue)
+	}
+
+	// Bool flags can only have their values combined (e.g.
+	// --verbose=false).
+	if flag.Bool {
+		return filterCompletions(CompleteValues("true", "false")(cur), prefix, cur, CompletionFlagValue)
+	}
+	return nil
+}
+
+// filterCompletions returns the given candidates which start with the given
+// partial value, with the given prefix prepended to their values and their
+// kind set to the given kind.
+func filterCompletions(completions []Completion, prefix string, cur string, kind CompletionKind) []Completion {
+	var filtered []Completion
+	for _, completion := range completions {
+		if !strings.HasPrefix(completion.Value, cur) {
+			continue
+		}
+		completion.Value = prefix + completion.Value
+		completion.Kind = kind
+		filtered = append(filtered, completion)
+	}
+	return filtered
+}
@@ -0,0 +1,353 @@
+package mojo_test
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ravernkoh/mojo"
+)
+
+func TestComplete(t *testing.T) {
+	conf := mojo.Config{
+		AllowMutipleFlags: true,
+		Root: mojo.CommandConfig{
+			Name: "tldr",
+			Commands: []mojo.CommandConfig{
+				{
+					Name:        "add",
+					Description: "Add a page.\nThe page is added to the local cache.",
+					Complete:    mojo.CompleteFiles(".golden"),
+					Flags: []mojo.FlagConfig{
+						{
+							Name:        "-f",
+							Bool:        true,
+							Description: "Don't ask for confirmation.",
+						},
+					},
+				},
+				{
+					Name: "update",
+					Arguments: []mojo.ArgumentConfig{
+						{Name: "PAGE"},
+						{Name: "PLATFORM", Complete: mojo.CompleteValues("linux", "osx")},
+					},
+				},
+			},
+			Flags: []mojo.FlagConfig{
+				{
+					Name:        "--verbose",
+					Aliases:     []string{"-v"},
+					Bool:        true,
+					Negatable:   true,
+					Description: "Enable verbose output.",
+				},
+				{
+					Name:        "--level",
+					Aliases:     []string{"-l"},
+					Description: "Set the user's level.",
+					Complete: func(cur string) []mojo.Completion {
+						return []mojo.Completion{
+							{Value: "1", Description: "Beginner"},
+							{Value: "2", Description: "Expert"},
+						}
+					},
+				},
+				{
+					Name: "-a",
+					Bool: true,
+				},
+				{
+					Name:    "--format",
+					Choices: []string{"json", "yaml", "text"},
+				},
+			},
+		},
+	}
+
+	type args struct {
+		args []string
+		i    int
+	}
+
+	type rets struct {
+		completions []mojo.Completion
+		err         error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "Command",
+			args: args{
+				args: []string{"tldr", "-v", "a"},
+				i:    2,
+			},
+			want: rets{
+				completions: []mojo.Completion{
+					{Value: "add", Description: "Add a page.", Kind: mojo.CompletionCommand},
+				},
+			},
+		},
+		{
+			name: "NewArgument",
+			args: args{
+				args: []string{"tldr"},
+				i:    1,
+			},
+			want: rets{
+				completions: []mojo.Completion{
+					{Value: "add", Description: "Add a page.", Kind: mojo.CompletionCommand},
+					{Value: "update", Kind: mojo.CompletionCommand},
+				},
+			},
+		},
+		{
+			name: "Flag",
+			args: args{
+				args: []string{"tldr", "--lev", "add"},
+				i:    1,
+			},
+			want: rets{
+				completions: []mojo.Completion{
+					{Value: "--level", Description: "Set the user's level.", Kind: mojo.CompletionFlag},
+				},
+			},
+		},
+		{
+			name: "SubcommandFlag",
+			args: args{
+				args: []string{"tldr", "add", "-"},
+				i:    2,
+			},
+			want: rets{
+				completions: []mojo.Completion{
+					{Value: "-f", Description: "Don't ask for confirmation.", Kind: mojo.CompletionFlag},
+					{Value: "--verbose", Description: "Enable verbose output.", Kind: mojo.CompletionFlag},
+					{Value: "-v", Description: "Enable verbose output.", Kind: mojo.CompletionFlag},
+					{Value: "--no-verbose", Description: "Enable verbose output.", Kind: mojo.CompletionFlag},
+					{Value: "--level", Description: "Set the user's level.", Kind: mojo.CompletionFlag},
+					{Value: "-l", Description: "Set the user's level.", Kind: mojo.CompletionFlag},
+					{Value: "-a", Kind: mojo.CompletionFlag},
+					{Value: "--format", Kind: mojo.CompletionFlag},
+				},
+			},
+		},
+		{
+			name: "MultipleFlags",
+			args: args{
+				args: []string{"tldr", "-av"},
+				i:    1,
+			},
+			want: rets{
+				completions: []mojo.Completion{
+					{Value: "-avl", Description: "Set the user's level.", Kind: mojo.CompletionFlag},
+				},
+			},
+		},
+		{
+			name: "FlagValue",
+			args: args{
+				args: []string{"tldr", "-avl", ""},
+				i:    2,
+			},
+			want: rets{
+				completions: []mojo.Completion{
+					{Value: "1", Description: "Beginner", Kind: mojo.CompletionFlagValue},
+					{Value: "2", Description: "Expert", Kind: mojo.CompletionFlagValue},
+				},
+			},
+		},
+		{
+			name: "CombinedFlagValue",
+			args: args{
+				args: []string{"tldr", "--level=2"},
+				i:    1,
+			},
+			want: rets{
+				completions: []mojo.Completion{
+					{Value: "--level=2", Description: "Expert", Kind: mojo.CompletionFlagValue},
+				},
+			},
+		},
+		{
+			name: "BoolFlagValue",
+			args: args{
+				args: []string{"tldr", "--verbose=f"},
+				i:    1,
+			},
+			want: rets{
+				completions: []mojo.Completion{
+					{Value: "--verbose=false", Kind: mojo.CompletionFlagValue},
+				},
+			},
+		},
+		{
+			name: "DoubleDash",
+			args: args{
+				args: []string{"tldr", "--", "a"},
+				i:    2,
+			},
+			want: rets{},
+		},
+		{
+			name: "Argument",
+			args: args{
+				args: []string{"tldr", "add", "testdata/completion.b"},
+				i:    2,
+			},
+			want: rets{
+				completions: []mojo.Completion{
+					{Value: "testdata/completion.bash.golden", Kind: mojo.CompletionArgument},
+				},
+			},
+		},
+		{
+			name: "ErrInvalidFlag",
+			args: args{
+				args: []string{"tldr", "--no-verbose=true", ""},
+				i:    2,
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid flag: --no-verbose"),
+			},
+		},
+		{
+			name: "NamedArgument",
+			args: args{
+				args: []string{"tldr", "update", "nmap", "l"},
+				i:    3,
+			},
+			want: rets{
+				completions: []mojo.Completion{
+					{Value: "linux", Kind: mojo.CompletionArgument},
+				},
+			},
+		},
+		{
+			name: "Choices",
+			args: args{
+				args: []string{"tldr", "--format", "j"},
+				i:    2,
+			},
+			want: rets{
+				completions: []mojo.Completion{
+					{Value: "json", Kind: mojo.CompletionFlagValue},
+				},
+			},
+		},
+		{
+			name: "NegatedFlag",
+			args: args{
+				args: []string{"tldr", "--no"},
+				i:    1,
+			},
+			want: rets{
+				completions: []mojo.Completion{
+					{Value: "--no-verbose", Description: "Enable verbose output.", Kind: mojo.CompletionFlag},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.completions, got.err = mojo.Complete(conf, test.args.args, test.args.i)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if !reflect.DeepEqual(got.completions, test.want.completions) {
+				t.Errorf("want completions %+v, got completions %+v", test.want.completions, got.completions)
+			}
+		})
+	}
+}
+
+func TestHandleComplete(t *testing.T) {
+	conf := mojo.Config{
+		Root: mojo.CommandConfig{
+			Name: "tldr",
+			Commands: []mojo.CommandConfig{
+				{
+					Name:        "add",
+					Description: "Add a page.",
+				},
+			},
+			Flags: []mojo.FlagConfig{
+				{
+					Name:     "--platform",
+					Complete: mojo.CompleteValues("linux", "osx"),
+				},
+			},
+		},
+	}
+
+	type args struct {
+		args []string
+	}
+
+	type rets struct {
+		ok     bool
+		output string
+		err    error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "NotHandled",
+			args: args{
+				args: []string{"tldr", "add"},
+			},
+			want: rets{},
+		},
+		{
+			name: "Command",
+			args: args{
+				args: []string{"tldr", "__complete"},
+			},
+			want: rets{
+				ok:     true,
+				output: "add\tAdd a page.\n",
+			},
+		},
+		{
+			name: "FlagValue",
+			args: args{
+				args: []string{"tldr", "__complete", "--platform", ""},
+			},
+			want: rets{
+				ok:     true,
+				output: "linux\nosx\n",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				got rets
+				b   bytes.Buffer
+			)
+			got.ok, got.err = mojo.HandleComplete(conf, test.args.args, &b)
+			got.output = b.String()
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if got.ok != test.want.ok {
+				t.Errorf("want ok %v, got ok %v", test.want.ok, got.ok)
+			}
+			if got.output != test.want.output {
+				t.Errorf("want output %q, got output %q", test.want.output, got.output)
+			}
+		})
+	}
+}
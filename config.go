@@ -1,5 +1,10 @@
 package mojo
 
+import (
+	"os"
+	"strings"
+)
+
 // Config contains configuration that defines how to parse certain objects.
 type Config struct {
 	Root CommandConfig
@@ -11,6 +16,15 @@ type Config struct {
 	// invalid flag error.
 	DisallowUnconfiguredFlags bool
 
+	// DisallowUnconfiguredCommands indicates whether unconfigured commands
+	// are not allowed.
+	//
+	// If it isn't allowed, then the first argument of a command that has
+	// subcommands and no configured arguments must be one of its
+	// subcommands, or a command not found error will occur, which
+	// suggests the closest subcommands.
+	DisallowUnconfiguredCommands bool
+
 	// AllowMultipleFlags indicates whether combining multiple flags
 	// (e.g. ls -al) is allowed.
 	//
@@ -30,41 +44,414 @@ type Config struct {
 	// DisallowDoubleDash indicates whether the double dash (i.e. --) is
 	// not allowed.
 	//
-	// If it is allowed, it will be parsed as an argument. However, if it
-	// isn't allowed, then an error will occur since it will be treated
-	// as a flag without a name.
+	// If it is allowed, it marks the end of flags and commands, and all
+	// arguments after it will be parsed as arguments. However, if it isn't
+	// allowed, then an error will occur since it will be treated as a flag
+	// without a name.
 	DisallowDoubleDash bool
+
+	// AllowNegativeNumbers indicates whether arguments that look like
+	// negative numbers (e.g. -5 or -1.5) are parsed as arguments or values
+	// of flags instead of flags.
+	//
+	// Flags in the configuration with such names (e.g. ls -1) are still
+	// parsed as flags.
+	AllowNegativeNumbers bool
+
+	// AllowFlagAbbreviations indicates whether long flags can be passed
+	// using unique prefixes of their names (e.g. --verb for --verbose).
+	//
+	// If it is allowed, a prefix matching more than one flag will result
+	// in an ambiguous flag error, which lists the matching flags. Flags in
+	// the configuration with such names are still parsed as they are.
+	AllowFlagAbbreviations bool
+
+	// AllowCommandAbbreviations indicates whether commands can be passed
+	// using unique prefixes of their names or aliases (e.g. rem for
+	// remove).
+	//
+	// A prefix matching more than one command is parsed as an argument.
+	// Commands in the configuration with such names are still parsed as
+	// they are.
+	AllowCommandAbbreviations bool
+
+	// EnvPrefix is the prefix used to derive the environment variable of
+	// flags that don't specify one.
+	//
+	// If it isn't empty, the environment variable of a flag is derived
+	// from the prefix and its name (e.g. the prefix TOOL and the flag
+	// --log-level will result in TOOL_LOG_LEVEL). Check Env in FlagConfig
+	// for more information.
+	EnvPrefix string
+
+	// LookupEnv is used to look up the values of environment variables.
+	//
+	// If it is nil, os.LookupEnv will be used.
+	LookupEnv func(key string) (string, bool)
+
+	// Files contains configuration files with values used if flags aren't
+	// passed.
+	//
+	// If a file contains a value for a flag, a flag with the value will be
+	// added to the parsed objects, with Source set to SourceFile. Values in
+	// later files take precedence over earlier ones. Environment variables
+	// take precedence over files, while files take precedence over
+	// default values.
+	Files []File
 }
 
 // CommandConfig contains configuration for a command.
 type CommandConfig struct {
-	Name     string
+	Name string
+
+	// Aliases contains other names for the command (e.g. rm for remove).
+	//
+	// Commands parsed using any of the aliases will resolve to the same
+	// command, with Name being the canonical name.
+	Aliases []string
+
 	Commands []CommandConfig
 	Flags    []FlagConfig
+
+	// Constraints contains the constraints on the flags passed to the
+	// command, which can include the flags of its parent commands.
+	//
+	// If the flags passed violate a constraint, then a constraint error
+	// will occur. Only flags parsed from the arguments are considered
+	// passed, not those with values from the environment, files or
+	// defaults.
+	Constraints []FlagConstraint
+
+	// Arguments contains the arguments of the command in order.
+	//
+	// If it isn't empty, passing fewer or more arguments to the command
+	// than configured will result in a too few or too many arguments
+	// error, and the parsed arguments will have their names set.
+	Arguments []ArgumentConfig
+
+	// Description describes the command in help output.
+	//
+	// Only the first line is used when the command is listed in the help
+	// output of its parent command.
+	Description string
+
+	// Usage contains the usage of the command in help output, excluding
+	// the names of the commands (e.g. [OPTIONS] FILE...).
+	//
+	// If it is empty, the usage will be generated from the configuration.
+	Usage string
+
+	// Group is the heading of the section that the command is listed
+	// under in the help output of its parent command.
+	//
+	// If it is empty, the command will be listed under Commands.
+	Group string
+
+	// Complete returns the candidates for completing the arguments of the
+	// command, which aren't completed by the configuration of the
+	// argument.
+	//
+	// Check Complete for more information.
+	Complete CompleteFunc
+}
+
+// FlagConstraint represents a constraint on the flags passed to a command.
+type FlagConstraint struct {
+	Kind ConstraintKind
+
+	// Flags contains the names or aliases of the flags in the constraint.
+	Flags []string
+}
+
+// ConstraintKind represents the kind of a constraint on flags.
+type ConstraintKind int
+
+// Possible constraint kinds.
+const (
+	// ExactlyOne indicates that exactly one of the flags must be passed.
+	ExactlyOne ConstraintKind = iota
+
+	// AtMostOne indicates that at most one of the flags can be passed.
+	AtMostOne
+
+	// AllOrNone indicates that either all or none of the flags must be
+	// passed.
+	AllOrNone
+
+	// Requires indicates that if the first flag is passed, the rest of the
+	// flags must be passed.
+	Requires
+)
+
+// ArgumentConfig contains configuration for an argument.
+type ArgumentConfig struct {
+	// Name is the name of the argument (e.g. SRC), which is used in help
+	// output and to get the argument from the parsed objects.
+	Name string
+
+	// Description describes the argument in help output.
+	Description string
+
+	// Optional indicates whether the argument can be left out.
+	Optional bool
+
+	// Variadic indicates whether the argument can be passed more than
+	// once (e.g. SRC... in cp SRC... DST).
+	Variadic bool
+
+	// Min and Max are the minimum and maximum number of times a variadic
+	// argument can be passed.
+	//
+	// If Min is zero, it is one, or zero if the argument is optional. If
+	// Max is zero, there is no maximum.
+	Min int
+	Max int
+
+	// Complete returns the candidates for completing the argument.
+	//
+	// Check Complete for more information.
+	Complete CompleteFunc
 }
 
 // FlagConfig contains configuration for a flag.
 type FlagConfig struct {
 	Name string
+
+	// Aliases contains other names for the flag (e.g. -v for --verbose).
+	//
+	// Flags parsed using any of the aliases will resolve to the same flag,
+	// with Name being the canonical name.
+	Aliases []string
+
 	Bool bool
+
+	// Negatable indicates whether the bool flag can be negated by adding
+	// no- to its long names (e.g. --no-color for --color).
+	//
+	// Flags passed using a negated name resolve to the same flag, with
+	// Negated set to indicate a false value.
+	Negatable bool
+
+	// Count indicates whether the bool flag counts the number of times it
+	// is passed (e.g. -vvv).
+	//
+	// Its value can also be passed directly (e.g. --verbose=3). Check
+	// Count in Objects for more information.
+	Count bool
+
+	// OptionalValue indicates whether the value of the flag is optional
+	// (e.g. --color[=WHEN]).
+	//
+	// If it is optional, the value is only taken from the same argument
+	// (e.g. --color=always or -calways), and the next argument is never
+	// used as the value. If the flag is passed without a value, its value
+	// will be Implicit.
+	OptionalValue bool
+
+	// Implicit contains the value of the flag with an optional value if it
+	// is passed without one.
+	Implicit string
+
+	// Description describes the flag in help output.
+	Description string
+
+	// ValueName is the name of the value of the flag in help output (e.g.
+	// LEVEL in --level=LEVEL).
+	//
+	// If it is empty, the name will be derived from the name of the flag.
+	ValueName string
+
+	// Group is the heading of the section that the flag is listed under in
+	// help output.
+	//
+	// If it is empty, the flag will be listed under Options.
+	Group string
+
+	// Env is the environment variable containing the value used if the
+	// flag isn't passed.
+	//
+	// If the environment variable is set, a flag with its value will be
+	// added to the parsed objects, with Source set to SourceEnv. Check
+	// Files in Config for the precedence of values.
+	Env string
+
+	// Default contains the value used if the flag isn't passed.
+	//
+	// If it isn't empty, a flag with the default value will be added to
+	// the parsed objects, with Source set to SourceDefault.
+	Default string
+
+	// Local indicates whether the flag can only be used in its command,
+	// instead of also being inherited by its subcommands.
+	//
+	// If it is local, then it will be parsed as an unconfigured flag in
+	// the subcommands. Inherited flags are listed under Global options in
+	// the help output of the subcommands.
+	Local bool
+
+	// Required indicates whether the flag must be passed.
+	//
+	// If it is required and has no default value, then a missing flag will
+	// result in a required flag error.
+	Required bool
+
+	// Choices contains the allowed values of the flag.
+	//
+	// If it isn't empty, then other values will result in an invalid
+	// choice error, which suggests the closest choices. The choices are
+	// also listed in help output and used for completion.
+	Choices []string
+
+	// Validate checks the value of the flag, returning an error if it is
+	// invalid (e.g. ValidateRange and ValidatePattern).
+	//
+	// The error will be wrapped in a flag error.
+	Validate func(value string) error
+
+	// Complete returns the candidates for completing the value of the
+	// flag.
+	//
+	// Check Complete for more information.
+	Complete CompleteFunc
 }
 
-// Command returns the command configuration for the command of the given name.
+// Command returns the command configuration for the command of the given name
+// or alias.
 func (c CommandConfig) Command(name string) (CommandConfig, bool) {
 	for _, cmd := range c.Commands {
-		if cmd.Name == name {
-			return cmd, true
+		for _, cmdName := range cmd.names() {
+			if cmdName == name {
+				return cmd, true
+			}
 		}
 	}
 	return CommandConfig{}, false
 }
 
-// Flag returns the flag configuration for the command of the given name.
+// names returns the name and aliases of the command.
+func (c CommandConfig) names() []string {
+	return append([]string{c.Name}, c.Aliases...)
+}
+
+// subcommandNames returns the names and aliases of the subcommands of the
+// command.
+func (c CommandConfig) subcommandNames() []string {
+	var names []string
+	for _, cmd := range c.Commands {
+		names = append(names, cmd.names()...)
+	}
+	return names
+}
+
+// argument returns the configuration of the argument which the argument at the
+// given index would most likely be passed to.
+//
+// Since the total number of arguments isn't known, variadic arguments are
+// assumed to take all the arguments after them.
+func (c CommandConfig) argument(i int) (ArgumentConfig, bool) {
+	for _, arg := range c.Arguments {
+		if arg.Variadic {
+			return arg, true
+		}
+		if i == 0 {
+			return arg, true
+		}
+		i--
+	}
+	return ArgumentConfig{}, false
+}
+
+// arity returns the minimum and maximum number of times the argument can be
+// passed, with a maximum of -1 indicating that there is no maximum.
+func (a ArgumentConfig) arity() (int, int) {
+	min := 1
+	if a.Optional {
+		min = 0
+	}
+	if !a.Variadic {
+		return min, 1
+	}
+
+	if a.Min > 0 {
+		min = a.Min
+	}
+	if a.Max > 0 {
+		return min, a.Max
+	}
+	return min, -1
+}
+
+// Flag returns the flag configuration for the flag of the given name or alias,
+// including negated names (e.g. --no-color).
 func (c CommandConfig) Flag(name string) (FlagConfig, bool) {
 	for _, flag := range c.Flags {
-		if flag.Name == name {
+		if flag.hasName(name) || flag.hasNegatedName(name) {
 			return flag, true
 		}
 	}
 	return FlagConfig{}, false
 }
+
+// hasName returns whether the given name is the name or one of the aliases of
+// the flag.
+func (f FlagConfig) hasName(name string) bool {
+	if f.Name == name {
+		return true
+	}
+	for _, alias := range f.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// names returns the name and aliases of the flag.
+func (f FlagConfig) names() []string {
+	return append([]string{f.Name}, f.Aliases...)
+}
+
+// negatedNames returns the negated names of the flag (e.g. --no-color), if it
+// is a negatable bool flag.
+func (f FlagConfig) negatedNames() []string {
+	if !f.Bool || !f.Negatable {
+		return nil
+	}
+
+	var names []string
+	for _, name := range f.names() {
+		if strings.HasPrefix(name, "--") {
+			names = append(names, "--no-"+name[2:])
+		}
+	}
+	return names
+}
+
+// hasNegatedName returns whether the given name is one of the negated names of
+// the flag.
+func (f FlagConfig) hasNegatedName(name string) bool {
+	for _, negatedName := range f.negatedNames() {
+		if negatedName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupEnv looks up the value of the environment variable of the given flag.
+func (c Config) lookupEnv(flag FlagConfig) (string, bool) {
+	key := flag.Env
+	if key == "" && c.EnvPrefix != "" {
+		name := strings.TrimLeft(flag.Name, "-")
+		name = strings.ToUpper(strings.Replace(name, "-", "_", -1))
+		key = c.EnvPrefix + "_" + name
+	}
+	if key == "" {
+		return "", false
+	}
+
+	if c.LookupEnv != nil {
+		return c.LookupEnv(key)
+	}
+	return os.LookupEnv(key)
+}
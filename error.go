@@ -1,6 +1,9 @@
 package mojo
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Possible wrapped errors.
 var (
@@ -8,6 +11,7 @@ var (
 	ErrIncompleteMultipleFlag = fmt.Errorf("mojo: incomplete multiple flag")
 	ErrFlagNotFound           = fmt.Errorf("mojo: flag not found")
 	ErrArgumentNotFound       = fmt.Errorf("mojo: argument not found")
+	ErrCommandNotFound        = fmt.Errorf("mojo: command not found")
 
 	// ErrUnconfiguredFlag occurs during parsing when a flag that does not
 	// exist in the configuration is found.
@@ -16,27 +20,203 @@ var (
 	// ErrUnexpectedArrayFlag occurs when more than one flag with the same name
 	// is found when only one is requested.
 	ErrUnexpectedArrayFlag = fmt.Errorf("mojo: unexpected array flag")
+
+	// ErrInvalidValue occurs when the value of a flag cannot be converted
+	// to the requested type.
+	ErrInvalidValue = fmt.Errorf("mojo: invalid value")
+
+	// ErrRequiredFlag occurs during parsing when a required flag is not
+	// passed.
+	ErrRequiredFlag = fmt.Errorf("mojo: required flag")
+
+	// ErrInvalidStruct occurs when the value given to be used as a struct
+	// is not a pointer to a struct.
+	ErrInvalidStruct = fmt.Errorf("mojo: invalid struct")
+
+	// ErrUnsupportedType occurs when a struct field has a type that cannot
+	// be used to store the values of flags.
+	ErrUnsupportedType = fmt.Errorf("mojo: unsupported type")
+
+	// ErrMultipleCommands occurs when more than one subcommand of the same
+	// command is set in a struct.
+	ErrMultipleCommands = fmt.Errorf("mojo: multiple commands")
+
+	// ErrInvalidFile occurs when a configuration file cannot be parsed.
+	ErrInvalidFile = fmt.Errorf("mojo: invalid file")
+
+	// ErrTooFewArguments occurs during parsing when fewer arguments than
+	// configured are passed to a command.
+	ErrTooFewArguments = fmt.Errorf("mojo: too few arguments")
+
+	// ErrTooManyArguments occurs during parsing when more arguments than
+	// configured are passed to a command.
+	ErrTooManyArguments = fmt.Errorf("mojo: too many arguments")
+
+	// ErrInvalidChoice occurs during parsing when the value of a flag is
+	// not one of its choices.
+	ErrInvalidChoice = fmt.Errorf("mojo: invalid choice")
+
+	// ErrOutOfRange occurs during parsing when the value of a flag is not
+	// within its range.
+	ErrOutOfRange = fmt.Errorf("mojo: value out of range")
+
+	// ErrPatternMismatch occurs during parsing when the value of a flag
+	// doesn't match its pattern.
+	ErrPatternMismatch = fmt.Errorf("mojo: value doesn't match pattern")
+
+	// ErrConflictingFlags occurs during parsing when more than one flag
+	// is passed when at most one is allowed.
+	ErrConflictingFlags = fmt.Errorf("mojo: conflicting flags")
+
+	// ErrMissingOneOfFlags occurs during parsing when none of the flags
+	// are passed when exactly one is required.
+	ErrMissingOneOfFlags = fmt.Errorf("mojo: missing one of flags")
+
+	// ErrIncompleteFlags occurs during parsing when some but not all of
+	// the flags that must be passed together are passed.
+	ErrIncompleteFlags = fmt.Errorf("mojo: incomplete flags")
+
+	// ErrMissingRequiredFlags occurs during parsing when a flag is passed
+	// without the flags it requires.
+	ErrMissingRequiredFlags = fmt.Errorf("mojo: missing required flags")
+
+	// ErrAmbiguousFlag occurs during parsing when an abbreviated flag
+	// matches more than one flag.
+	ErrAmbiguousFlag = fmt.Errorf("mojo: ambiguous flag")
+
+	// ErrUnexpectedArrayArgument occurs when more than one argument with
+	// the same name is found when only one is requested.
+	ErrUnexpectedArrayArgument = fmt.Errorf("mojo: unexpected array argument")
 )
 
 // FlagError represents a flag error.
 type FlagError struct {
 	Name string
 	Err  error
+
+	// Choices contains the valid values of the flag, if any.
+	Choices []string
+
+	// Suggestions contains the closest valid values to the value of the
+	// flag, or the closest flags to an unconfigured or ambiguous flag, if
+	// any.
+	Suggestions []string
 }
 
 func (err FlagError) Error() string {
-	if err.Name == "" {
-		return err.Err.Error()
+	msg := err.Err.Error()
+	if err.Name != "" {
+		msg = fmt.Sprintf("%v: %s", err.Err, err.Name)
 	}
-	return fmt.Sprintf("%v: %s", err.Err, err.Name)
+	if len(err.Choices) > 0 {
+		msg += fmt.Sprintf(" (choices: %s)", strings.Join(err.Choices, ", "))
+	}
+	if len(err.Suggestions) > 0 {
+		msg += fmt.Sprintf(" (did you mean %s?)", strings.Join(err.Suggestions, " or "))
+	}
+	return msg
+}
+
+// Unwrap returns the wrapped error.
+func (err FlagError) Unwrap() error {
+	return err.Err
+}
+
+// CommandError represents a command error.
+type CommandError struct {
+	Name string
+	Err  error
+
+	// Suggestions contains the commands closest to the name of the
+	// command, if any.
+	Suggestions []string
+}
+
+func (err CommandError) Error() string {
+	msg := fmt.Sprintf("%v: %s", err.Err, err.Name)
+	if len(err.Suggestions) > 0 {
+		msg += fmt.Sprintf(" (did you mean %s?)", strings.Join(err.Suggestions, " or "))
+	}
+	return msg
+}
+
+// Unwrap returns the wrapped error.
+func (err CommandError) Unwrap() error {
+	return err.Err
 }
 
 // ArgumentError represents an argument error.
 type ArgumentError struct {
 	Index int
-	Err   error
+
+	// Name is the name of the argument in the configuration, if any.
+	Name string
+	Err  error
 }
 
 func (err ArgumentError) Error() string {
+	if err.Name != "" {
+		return fmt.Sprintf("%v: %s", err.Err, err.Name)
+	}
 	return fmt.Sprintf("%v: %d", err.Err, err.Index)
 }
+
+// Unwrap returns the wrapped error.
+func (err ArgumentError) Unwrap() error {
+	return err.Err
+}
+
+// FileError represents a configuration file error.
+type FileError struct {
+	Name string
+	Line int
+	Err  error
+}
+
+func (err FileError) Error() string {
+	return fmt.Sprintf("%v: %s:%d", err.Err, err.Name, err.Line)
+}
+
+// Unwrap returns the wrapped error.
+func (err FileError) Unwrap() error {
+	return err.Err
+}
+
+// FieldError represents a struct field error.
+type FieldError struct {
+	Name string
+	Err  error
+}
+
+func (err FieldError) Error() string {
+	return fmt.Sprintf("%v: %s", err.Err, err.Name)
+}
+
+// Unwrap returns the wrapped error.
+func (err FieldError) Unwrap() error {
+	return err.Err
+}
+
+// ConstraintError represents an error of the flags passed violating a
+// constraint.
+type ConstraintError struct {
+	// Names contains the names of the offending flags.
+	Names []string
+
+	// Flag is the name of the flag requiring the offending flags, if the
+	// constraint is a Requires constraint.
+	Flag string
+	Err  error
+}
+
+func (err ConstraintError) Error() string {
+	if err.Flag != "" {
+		return fmt.Sprintf("%v: %s (required by %s)", err.Err, strings.Join(err.Names, ", "), err.Flag)
+	}
+	return fmt.Sprintf("%v: %s", err.Err, strings.Join(err.Names, ", "))
+}
+
+// Unwrap returns the wrapped error.
+func (err ConstraintError) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,315 @@
+package mojo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// File represents a configuration file containing values of flags.
+//
+// Check Files in Config for more information.
+type File struct {
+	// Name is the name of the file, which is used when describing where a
+	// value came from.
+	Name   string
+	Values []FileValue
+}
+
+// FileValue represents a value in a configuration file.
+type FileValue struct {
+	// Commands contains the names of the subcommands that the value is
+	// for, excluding the root command.
+	Commands []string
+
+	// Key is the name of the flag that the value is for.
+	//
+	// It is matched against the names and aliases of flags without the
+	// leading dashes, ignoring case and treating underscores as dashes
+	// (e.g. LOG_LEVEL matches --log-level).
+	Key   string
+	Value string
+
+	// Line is the line number of the value in the file, starting from 1.
+	Line int
+}
+
+// ReadFile reads and parses the configuration file with the given name.
+//
+// Files with the .json extension are parsed using ParseJSONFile, while all
+// other files are parsed using ParseINIFile.
+func ReadFile(name string) (File, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return File{}, err
+	}
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		return ParseJSONFile(name, data)
+	}
+	return ParseINIFile(name, data)
+}
+
+// ParseINIFile parses the given data as an INI or dotenv style configuration
+// file with the given name.
+//
+// Each line contains a key and value separated by an equals sign (e.g.
+// level = 5), optionally prefixed by export. Values can be quoted using single
+// or double quotes. Sections (e.g. [remote.add]) contain the values for the
+// subcommands separated by dots. Empty lines and lines starting with # or ; are
+// ignored.
+func ParseINIFile(name string, data []byte) (File, error) {
+	file := File{Name: name}
+
+	var (
+		commands []string
+		line     int
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+
+		// Skip empty lines and comments.
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		// Check for sections.
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return File{}, FileError{Name: name, Line: line, Err: ErrInvalidFile}
+			}
+
+			commands = nil
+			if section := strings.TrimSpace(text[1 : len(text)-1]); section != "" {
+				commands = strings.Split(section, ".")
+			}
+			continue
+		}
+
+		text = strings.TrimPrefix(text, "export ")
+
+		i := strings.Index(text, "=")
+		if i == -1 {
+			return File{}, FileError{Name: name, Line: line, Err: ErrInvalidFile}
+		}
+
+		key := strings.TrimSpace(text[:i])
+		value := strings.TrimSpace(text[i+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		file.Values = append(file.Values, FileValue{
+			Commands: commands,
+			Key:      key,
+			Value:    value,
+			Line:     line,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return File{}, err
+	}
+
+	return file, nil
+}
+
+// ParseJSONFile parses the given data as a JSON configuration file with the
+// given name.
+//
+// The file must contain an object, where each key and value is the name and
+// value of a flag. Arrays contain the values of repeated flags, while nested
+// objects contain the values for the subcommand with the name of their key.
+func ParseJSONFile(name string, data []byte) (File, error) {
+	file := File{Name: name}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	// lineAt returns the line number of the current position of the
+	// decoder.
+	lineAt := func() int {
+		return bytes.Count(data[:dec.InputOffset()], []byte("\n")) + 1
+	}
+	invalid := func() error {
+		return FileError{Name: name, Line: lineAt(), Err: ErrInvalidFile}
+	}
+
+	// parseObject parses an object whose opening brace has already been
+	// read, for the given subcommands.
+	var parseObject func(commands []string) error
+	parseObject = func(commands []string) error {
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return invalid()
+			}
+			key := tok.(string)
+
+			values, err := parseJSONValues(dec, lineAt)
+			if err != nil {
+				return invalid()
+			}
+
+			// A nested object contains the values of a subcommand.
+			if values == nil {
+				subcommands := append(append([]string(nil), commands...), key)
+				if err := parseObject(subcommands); err != nil {
+					return err
+				}
+				if _, err := dec.Token(); err != nil {
+					return invalid()
+				}
+				continue
+			}
+
+			for _, value := range values {
+				value.Commands = commands
+				value.Key = key
+				file.Values = append(file.Values, value)
+			}
+		}
+		return nil
+	}
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return File{}, invalid()
+	}
+	if err := parseObject(nil); err != nil {
+		return File{}, err
+	}
+	if _, err := dec.Token(); err != nil {
+		return File{}, invalid()
+	}
+
+	return file, nil
+}
+
+// parseJSONValues parses the next value from the given decoder into file
+// values, using the given function to determine line numbers.
+//
+// If the next value is an object, only its opening brace is read and nil is
+// returned.
+func parseJSONValues(dec *json.Decoder, lineAt func() int) ([]FileValue, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		return nil, nil
+	case json.Delim('['):
+		values := []FileValue{}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, ok := jsonScalar(tok)
+			if !ok {
+				return nil, ErrInvalidFile
+			}
+			values = append(values, FileValue{Value: value, Line: lineAt()})
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+
+	// Null values are left out.
+	if tok == nil {
+		return []FileValue{}, nil
+	}
+
+	value, ok := jsonScalar(tok)
+	if !ok {
+		return nil, ErrInvalidFile
+	}
+	return []FileValue{{Value: value, Line: lineAt()}}, nil
+}
+
+// jsonScalar returns the given JSON token as a string if it is a string,
+// number or bool.
+func jsonScalar(tok json.Token) (string, bool) {
+	switch tok := tok.(type) {
+	case string:
+		return tok, true
+	case json.Number:
+		return tok.String(), true
+	case bool:
+		if tok {
+			return "true", true
+		}
+		return "false", true
+	}
+	return "", false
+}
+
+// lookupFiles looks up the values of the given flag of the command at the top
+// of the given command stack in the configuration files.
+//
+// The values of the last file containing the flag are used.
+func (c Config) lookupFiles(commands []string, flag FlagConfig) []FlagObject {
+	for i := len(c.Files) - 1; i >= 0; i-- {
+		file := c.Files[i]
+
+		var objs []FlagObject
+		for _, value := range file.Values {
+			if !equalCommands(value.Commands, commands[1:]) || !flag.hasKey(value.Key) {
+				continue
+			}
+			objs = append(objs, FlagObject{
+				Name:      flag.Name,
+				Value:     value.Value,
+				Canonical: flag.Name,
+				Aliases:   flag.Aliases,
+				Source:    SourceFile,
+				File:      file.Name,
+				Line:      value.Line,
+			})
+		}
+		if len(objs) > 0 {
+			return objs
+		}
+	}
+	return nil
+}
+
+// hasKey returns whether the given configuration file key matches the name or
+// one of the aliases of the flag.
+func (f FlagConfig) hasKey(key string) bool {
+	normalize := func(name string) string {
+		name = strings.TrimLeft(name, "-")
+		return strings.ToLower(strings.Replace(name, "_", "-", -1))
+	}
+
+	key = normalize(key)
+	if normalize(f.Name) == key {
+		return true
+	}
+	for _, alias := range f.Aliases {
+		if normalize(alias) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// equalCommands returns whether the given command stacks are equal.
+func equalCommands(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package mojo_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ravernkoh/mojo"
+)
+
+func TestParseINIFile(t *testing.T) {
+	type args struct {
+		name string
+		data string
+	}
+
+	type rets struct {
+		file mojo.File
+		err  error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrInvalidFile",
+			args: args{
+				name: "tldr.ini",
+				data: "level = 5\n\nverbose\n",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid file: tldr.ini:3"),
+			},
+		},
+		{
+			name: "Sections",
+			args: args{
+				name: "tldr.ini",
+				data: "# Comment.\nlevel = 5\n\n[add]\n; Comment.\nexport FORCE=\"true\"\n\n[remote.add]\nname = 'origin'\n",
+			},
+			want: rets{
+				file: mojo.File{
+					Name: "tldr.ini",
+					Values: []mojo.FileValue{
+						{Key: "level", Value: "5", Line: 2},
+						{Commands: []string{"add"}, Key: "FORCE", Value: "true", Line: 6},
+						{Commands: []string{"remote", "add"}, Key: "name", Value: "origin", Line: 9},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.file, got.err = mojo.ParseINIFile(test.args.name, []byte(test.args.data))
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if !reflect.DeepEqual(got.file, test.want.file) {
+				t.Errorf("want file %v, got file %v", test.want.file, got.file)
+			}
+		})
+	}
+}
+
+func TestParseJSONFile(t *testing.T) {
+	type args struct {
+		name string
+		data string
+	}
+
+	type rets struct {
+		file mojo.File
+		err  error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrInvalidFile",
+			args: args{
+				name: "tldr.json",
+				data: "{\n  \"level\": 5,\n  \"tags\": [{}]\n}\n",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid file: tldr.json:3"),
+			},
+		},
+		{
+			name: "Subcommands",
+			args: args{
+				name: "tldr.json",
+				data: "{\n  \"level\": 5,\n  \"verbose\": true,\n  \"pager\": null,\n  \"add\": {\n    \"tag\": [\"a\",\n      \"b\"]\n  }\n}\n",
+			},
+			want: rets{
+				file: mojo.File{
+					Name: "tldr.json",
+					Values: []mojo.FileValue{
+						{Key: "level", Value: "5", Line: 2},
+						{Key: "verbose", Value: "true", Line: 3},
+						{Commands: []string{"add"}, Key: "tag", Value: "a", Line: 6},
+						{Commands: []string{"add"}, Key: "tag", Value: "b", Line: 7},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.file, got.err = mojo.ParseJSONFile(test.args.name, []byte(test.args.data))
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if !reflect.DeepEqual(got.file, test.want.file) {
+				t.Errorf("want file %v, got file %v", test.want.file, got.file)
+			}
+		})
+	}
+}
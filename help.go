@@ -0,0 +1,306 @@
+package mojo
+
+import (
+	"strings"
+)
+
+const (
+	// helpIndent is the indentation of the rows in help output.
+	helpIndent = 2
+
+	// helpGap is the gap between the columns of the rows in help output.
+	helpGap = 2
+
+	// helpMaxColumn is the maximum width of the first column of the rows
+	// in help output. Rows with longer first columns will have their
+	// descriptions placed on the next line.
+	helpMaxColumn = 30
+
+	// helpDefaultWidth is the width used if the given width is not
+	// positive.
+	helpDefaultWidth = 80
+)
+
+// Help returns the help output of the command at the top of the given command
+// stack, wrapped to the given width.
+//
+// The first command given should be the name of the root command (e.g. git),
+// and is not checked. If the width is not positive, a width of 80 is used.
+func Help(conf Config, commands []string, width int) (string, error) {
+	if len(commands) < 1 {
+		panic("runtime error: index out of bounds")
+	}
+	if width <= 0 {
+		width = helpDefaultWidth
+	}
+
+	// Ensure the command stack is safe.
+	cmd := conf.Root
+	for _, name := range commands[1:] {
+		subcmd, ok := cmd.Command(name)
+		if !ok {
+			return "", CommandError{
+				Name:        name,
+				Err:         ErrCommandNotFound,
+				Suggestions: suggestions(name, cmd.subcommandNames()),
+			}
+		}
+		cmd = subcmd
+	}
+
+	var b strings.Builder
+
+	// Write the usage.
+	usage := cmd.Usage
+	if usage == "" {
+		usage = helpUsage(conf, commands)
+	}
+	b.WriteString(strings.Join(wrapText("Usage: "+strings.Join(commands, " ")+" "+usage, width), "\n"))
+	b.WriteString("\n")
+
+	// Write the description.
+	if cmd.Description != "" {
+		b.WriteString("\n")
+		b.WriteString(strings.Join(wrapText(cmd.Description, width), "\n"))
+		b.WriteString("\n")
+	}
+
+	// Write the arguments, commands and flags in their sections.
+	var sections []helpSection
+	for _, arg := range cmd.Arguments {
+		sections = addHelpRow(sections, "", "Arguments", helpRow{
+			name:        arg.Name,
+			description: arg.Description,
+		})
+	}
+	for _, subcmd := range cmd.Commands {
+		sections = addHelpRow(sections, subcmd.Group, "Commands", helpRow{
+			name:        strings.Join(subcmd.names(), ", "),
+			description: strings.SplitN(subcmd.Description, "\n", 2)[0],
+		})
+	}
+	for _, flag := range cmd.Flags {
+		sections = addHelpRow(sections, flag.Group, "Options", helpRow{
+			name:        helpFlagNames(flag),
+			description: helpFlagDescription(flag),
+		})
+	}
+
+	// Write the flags inherited from the parent commands in their own
+	// section.
+	for _, flag := range visibleFlags(conf, commands) {
+		if _, ok := cmd.Flag(flag.Name); ok {
+			continue
+		}
+		sections = addHelpRow(sections, "", "Global options", helpRow{
+			name:        helpFlagNames(flag),
+			description: helpFlagDescription(flag),
+		})
+	}
+
+	// Determine the width of the first column, which is shared by all
+	// the sections.
+	var column int
+	for _, section := range sections {
+		for _, row := range section.rows {
+			if len(row.name) > column && len(row.name) <= helpMaxColumn {
+				column = len(row.name)
+			}
+		}
+	}
+
+	for _, section := range sections {
+		b.WriteString("\n")
+		section.write(&b, column, width)
+	}
+
+	return b.String(), nil
+}
+
+// helpUsage returns the usage generated from the configuration of the command
+// at the top of the given command stack.
+func helpUsage(conf Config, commands []string) string {
+	var parts []string
+
+	cmds := configCommands(conf, commands)
+	for _, cmd := range cmds {
+		if len(cmd.Flags) > 0 {
+			parts = append(parts, "[OPTIONS]")
+			break
+		}
+	}
+	for _, arg := range cmds[0].Arguments {
+		parts = append(parts, helpArgumentName(arg))
+	}
+	if len(cmds[0].Commands) > 0 {
+		parts = append(parts, "COMMAND")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// helpArgumentName returns the name of the given argument in help output (e.g.
+// SRC... or [DST]).
+func helpArgumentName(arg ArgumentConfig) string {
+	name := arg.Name
+	if arg.Variadic {
+		name += "..."
+	}
+	if min, _ := arg.arity(); min == 0 {
+		name = "[" + name + "]"
+	}
+	return name
+}
+
+// helpFlagNames returns the names of the given flag in help output (e.g.
+// -l, --level=LEVEL, --color[=WHEN] or --[no-]color), with the short names
+// first.
+func helpFlagNames(flag FlagConfig) string {
+	var shortNames, longNames []string
+	for _, name := range append([]string{flag.Name}, flag.Aliases...) {
+		if strings.HasPrefix(name, "--") {
+			if flag.hasNegatedName("--no-" + name[2:]) {
+				name = "--[no-]" + name[2:]
+			}
+			longNames = append(longNames, name)
+		} else {
+			shortNames = append(shortNames, name)
+		}
+	}
+	names := strings.Join(append(shortNames, longNames...), ", ")
+
+	// Align long names with the long names of flags with short names.
+	if len(shortNames) == 0 {
+		names = "    " + names
+	}
+
+	if flag.Bool {
+		return names
+	}
+
+	valueName := flag.ValueName
+	if valueName == "" {
+		valueName = strings.TrimLeft(flag.Name, "-")
+		valueName = strings.ToUpper(strings.Replace(valueName, "-", "_", -1))
+	}
+	if flag.OptionalValue && len(longNames) > 0 {
+		return names + "[=" + valueName + "]"
+	}
+	if flag.OptionalValue {
+		return names + "[" + valueName + "]"
+	}
+	if len(longNames) > 0 {
+		return names + "=" + valueName
+	}
+	return names + " " + valueName
+}
+
+// helpFlagDescription returns the description of the given flag in help
+// output, including its choices and default value.
+func helpFlagDescription(flag FlagConfig) string {
+	var parts []string
+	if flag.Description != "" {
+		parts = append(parts, flag.Description)
+	}
+	if len(flag.Choices) > 0 {
+		parts = append(parts, "(choices: "+strings.Join(flag.Choices, ", ")+")")
+	}
+	if flag.Default != "" {
+		parts = append(parts, "(default: "+flag.Default+")")
+	}
+	if flag.Required {
+		parts = append(parts, "(required)")
+	}
+	return strings.Join(parts, " ")
+}
+
+// helpSection represents a section in help output.
+type helpSection struct {
+	heading string
+	rows    []helpRow
+}
+
+// helpRow represents a row in a section in help output.
+type helpRow struct {
+	name        string
+	description string
+}
+
+// addHelpRow adds the given row to the section with the given group as its
+// heading, or the given default heading if the group is empty. The section
+// will be created if it doesn't exist.
+func addHelpRow(sections []helpSection, group string, defaultHeading string, row helpRow) []helpSection {
+	heading := group
+	if heading == "" {
+		heading = defaultHeading
+	}
+
+	for i := range sections {
+		if sections[i].heading == heading {
+			sections[i].rows = append(sections[i].rows, row)
+			return sections
+		}
+	}
+	return append(sections, helpSection{
+		heading: heading,
+		rows:    []helpRow{row},
+	})
+}
+
+// write writes the section, with the given width of the first column, wrapped
+// to the given width into the given builder.
+func (s helpSection) write(b *strings.Builder, column int, width int) {
+	b.WriteString(s.heading + ":\n")
+
+	indent := strings.Repeat(" ", helpIndent)
+	descIndent := strings.Repeat(" ", helpIndent+column+helpGap)
+
+	descWidth := width - len(descIndent)
+	if descWidth < helpMaxColumn {
+		descWidth = helpMaxColumn
+	}
+
+	for _, row := range s.rows {
+		b.WriteString(indent + row.name)
+		if row.description == "" {
+			b.WriteString("\n")
+			continue
+		}
+
+		// Place the description on the next line if the name is too
+		// long.
+		if len(row.name) > column {
+			b.WriteString("\n" + descIndent)
+		} else {
+			b.WriteString(strings.Repeat(" ", column-len(row.name)+helpGap))
+		}
+
+		b.WriteString(strings.Join(wrapText(row.description, descWidth), "\n"+descIndent))
+		b.WriteString("\n")
+	}
+}
+
+// wrapText wraps the given text into lines with at most the given width,
+// breaking lines at spaces. Words longer than the width are not broken.
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		line := words[0]
+		for _, word := range words[1:] {
+			if len(line)+1+len(word) > width {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,214 @@
+package mojo_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ravernkoh/mojo"
+)
+
+func TestHelp(t *testing.T) {
+	conf := mojo.Config{
+		Root: mojo.CommandConfig{
+			Name:        "tldr",
+			Description: "Simplified and community-driven man pages.",
+			Commands: []mojo.CommandConfig{
+				{
+					Name:        "add",
+					Description: "Add a page.\nThe page is added to the local cache.",
+					Usage:       "[OPTIONS] PAGE",
+					Flags: []mojo.FlagConfig{
+						{
+							Name:        "--force",
+							Aliases:     []string{"-f"},
+							Bool:        true,
+							Description: "Overwrite the page if it already exists in the local cache, without asking for confirmation.",
+						},
+					},
+				},
+				{
+					Name:        "copy",
+					Description: "Copy pages.",
+					Arguments: []mojo.ArgumentConfig{
+						{
+							Name:        "SRC",
+							Description: "Pages to copy.",
+							Variadic:    true,
+						},
+						{
+							Name:        "DST",
+							Description: "Destination directory.",
+						},
+					},
+				},
+				{
+					Name:        "update",
+					Aliases:     []string{"up"},
+					Description: "Update the local cache.",
+					Group:       "Cache commands",
+				},
+				{
+					Name:  "clear",
+					Group: "Cache commands",
+				},
+			},
+			Flags: []mojo.FlagConfig{
+				{
+					Name:        "--verbose",
+					Aliases:     []string{"-v"},
+					Bool:        true,
+					Negatable:   true,
+					Description: "Enable verbose output.",
+				},
+				{
+					Name:        "--log-level",
+					Description: "Set the log level.",
+					Default:     "1",
+					Local:       true,
+				},
+				{
+					Name:        "-p",
+					ValueName:   "PLATFORM",
+					Description: "Override the platform.",
+					Required:    true,
+					Local:       true,
+				},
+				{
+					Name:        "--cache-directory",
+					Description: "Override the cache directory.",
+					Local:       true,
+				},
+				{
+					Name:          "--color",
+					ValueName:     "WHEN",
+					Choices:       []string{"auto", "always", "never"},
+					OptionalValue: true,
+					Implicit:      "always",
+					Description:   "Colorize the output.",
+					Group:         "Output options",
+					Local:         true,
+				},
+			},
+		},
+	}
+
+	type args struct {
+		commands []string
+		width    int
+	}
+
+	type rets struct {
+		help string
+		err  error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrCommandNotFound",
+			args: args{
+				commands: []string{"tldr", "remove"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: command not found: remove"),
+			},
+		},
+		{
+			name: "ErrCommandNotFoundSuggestions",
+			args: args{
+				commands: []string{"tldr", "cpy"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: command not found: cpy (did you mean copy?)"),
+			},
+		},
+		{
+			name: "Root",
+			args: args{
+				commands: []string{"tldr"},
+			},
+			want: rets{
+				help: `Usage: tldr [OPTIONS] COMMAND
+
+Simplified and community-driven man pages.
+
+Commands:
+  add                        Add a page.
+  copy                       Copy pages.
+
+Cache commands:
+  update, up                 Update the local cache.
+  clear
+
+Options:
+  -v, --[no-]verbose         Enable verbose output.
+      --log-level=LOG_LEVEL  Set the log level. (default: 1)
+  -p PLATFORM                Override the platform. (required)
+      --cache-directory=CACHE_DIRECTORY
+                             Override the cache directory.
+
+Output options:
+      --color[=WHEN]         Colorize the output. (choices: auto, always, never)
+`,
+			},
+		},
+		{
+			name: "SubcommandWrapped",
+			args: args{
+				commands: []string{"tldr", "add"},
+				width:    50,
+			},
+			want: rets{
+				help: `Usage: tldr add [OPTIONS] PAGE
+
+Add a page.
+The page is added to the local cache.
+
+Options:
+  -f, --force         Overwrite the page if it
+                      already exists in the local
+                      cache, without asking for
+                      confirmation.
+
+Global options:
+  -v, --[no-]verbose  Enable verbose output.
+`,
+			},
+		},
+		{
+			name: "Arguments",
+			args: args{
+				commands: []string{"tldr", "copy"},
+			},
+			want: rets{
+				help: `Usage: tldr copy [OPTIONS] SRC... DST
+
+Copy pages.
+
+Arguments:
+  SRC                 Pages to copy.
+  DST                 Destination directory.
+
+Global options:
+  -v, --[no-]verbose  Enable verbose output.
+`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.help, got.err = mojo.Help(conf, test.args.commands, test.args.width)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if got.help != test.want.help {
+				t.Errorf("want help\n%s\ngot help\n%s", test.want.help, got.help)
+			}
+		})
+	}
+}
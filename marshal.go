@@ -0,0 +1,256 @@
+package mojo
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Marshal returns the objects representing the struct pointed to by v, with
+// the root command having the given name. The objects can then be assembled
+// into arguments using Assemble.
+//
+// Flags with zero values are left out, while slices and maps result in a flag
+// for each of their elements. The subcommand, if any, is placed after the
+// flags of its parent command, even if none of its flags are set. Only one
+// subcommand field in each struct can be set, and subcommand fields must be
+// pointers to structs, since whether a struct subcommand was set couldn't be
+// told. Struct subcommand fields result in an unsupported type error.
+//
+// In addition to the tags described in Unmarshal, flag fields can have the
+// following options after their names:
+//
+//	combined  the flag will be combined with its value (i.e. --flag=value)
+//	cluster   the flag will be combined with other adjacent bool flags with
+//	          the cluster option (e.g. -al)
+//
+// Counting flags are passed in their compact form, which is -vvv for short
+// flags and --verbose=3 for long flags. Note that the compact form of short
+// flags can only be parsed if AllowMutipleFlags in Config is set.
+//
+// Check Unmarshal for more information on the tags used.
+func Marshal(name string, v interface{}) (Objects, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, ErrInvalidStruct
+	}
+	return marshalCommand(name, rv.Elem())
+}
+
+// marshalCommand returns the objects representing the given struct, with the
+// command having the given name.
+func marshalCommand(name string, rv reflect.Value) ([]Object, error) {
+	objs := []Object{CommandObject{Name: name}}
+
+	var (
+		flagObjs []FlagObject
+		clusters []bool
+		subobjs  []Object
+	)
+
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := rv.Field(i)
+
+		tag, ok := parseFieldTag(field)
+		if !ok {
+			continue
+		}
+		if tag.command && field.Type.Kind() != reflect.Ptr {
+			return nil, FieldError{
+				Name: field.Name,
+				Err:  ErrUnsupportedType,
+			}
+		}
+		if fieldValue.IsZero() {
+			continue
+		}
+
+		if tag.command {
+			// Only one subcommand can be set.
+			if subobjs != nil {
+				return nil, FieldError{
+					Name: field.Name,
+					Err:  ErrMultipleCommands,
+				}
+			}
+
+			var err error
+			subobjs, err = marshalCommand(tag.names[0], fieldValue.Elem())
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		fieldFlagObjs, err := marshalField(tag, fieldValue)
+		if err != nil {
+			return nil, FieldError{
+				Name: field.Name,
+				Err:  err,
+			}
+		}
+		for _, flagObj := range fieldFlagObjs {
+			flagObjs = append(flagObjs, flagObj)
+
+			// Counting flags are already in their compact form.
+			clusters = append(clusters, tag.hasOption("cluster") && !flagObj.Counter)
+		}
+	}
+
+	clusterFlags(flagObjs, clusters)
+	for _, flagObj := range flagObjs {
+		objs = append(objs, flagObj)
+	}
+
+	return append(objs, subobjs...), nil
+}
+
+// marshalField returns the flags representing the given field value.
+func marshalField(tag fieldTag, rv reflect.Value) ([]FlagObject, error) {
+	var flagObjs []FlagObject
+
+	if tag.isCounter(rv.Type()) {
+		return marshalCounter(tag, rv.Int()), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if !isValueType(rv.Type()) {
+			return nil, ErrUnsupportedType
+		}
+
+		// Sort the keys so that the flags are in a consistent order.
+		keys := make([]string, 0, rv.Len())
+		for _, key := range rv.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			value := rv.MapIndex(reflect.ValueOf(key)).String()
+			flagObjs = append(flagObjs, newMarshalFlag(tag, key+"="+value, false))
+		}
+	case reflect.Slice:
+		for i := 0; i < rv.Len(); i++ {
+			flagObj, err := marshalValue(tag, rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			flagObjs = append(flagObjs, flagObj)
+		}
+	default:
+		flagObj, err := marshalValue(tag, rv)
+		if err != nil {
+			return nil, err
+		}
+		flagObjs = append(flagObjs, flagObj)
+	}
+
+	return flagObjs, nil
+}
+
+// marshalCounter returns the flags representing the counting flag passed the
+// given number of times, in its compact form (e.g. -vvv or --verbose=3).
+func marshalCounter(tag fieldTag, count int64) []FlagObject {
+	obj := newMarshalFlag(tag, "", true)
+	obj.Counter = true
+	if count == 1 {
+		return []FlagObject{obj}
+	}
+
+	// Short flags are passed as multiple flags.
+	if count > 1 && len(obj.Name) == 2 && !strings.HasPrefix(obj.Name, "--") {
+		flagObjs := make([]FlagObject, count)
+		for i := range flagObjs {
+			flagObjs[i] = obj
+		}
+		flagObjs[0].MultipleFlagsStart = true
+		flagObjs[count-1].MultipleFlagsEnd = true
+		return flagObjs
+	}
+
+	obj.Bool = false
+	obj.Value = strconv.FormatInt(count, 10)
+	obj.CombinedFlagValues = true
+	return []FlagObject{obj}
+}
+
+// marshalValue returns the flag representing the given value.
+func marshalValue(tag fieldTag, rv reflect.Value) (FlagObject, error) {
+	if rv.Type() == durationType {
+		return newMarshalFlag(tag, time.Duration(rv.Int()).String(), false), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return newMarshalFlag(tag, rv.String(), false), nil
+	case reflect.Bool:
+		if rv.Bool() {
+			return newMarshalFlag(tag, "", true), nil
+		}
+
+		// A false bool flag must be combined with its value, since the
+		// value would otherwise be parsed as an argument.
+		obj := newMarshalFlag(tag, "false", false)
+		obj.CombinedFlagValues = true
+		return obj, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return newMarshalFlag(tag, strconv.FormatInt(rv.Int(), 10), false), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return newMarshalFlag(tag, strconv.FormatUint(rv.Uint(), 10), false), nil
+	case reflect.Float32, reflect.Float64:
+		return newMarshalFlag(tag, strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits()), false), nil
+	}
+	return FlagObject{}, ErrUnsupportedType
+}
+
+// newMarshalFlag creates a new flag with the given value for the given tag.
+func newMarshalFlag(tag fieldTag, value string, isBool bool) FlagObject {
+	obj := FlagObject{
+		Name:      tag.names[0],
+		Canonical: tag.names[0],
+		Bool:      isBool,
+	}
+	if len(tag.names) > 1 {
+		obj.Aliases = tag.names[1:]
+	}
+	if !isBool {
+		obj.Value = value
+		obj.CombinedFlagValues = tag.hasOption("combined")
+	}
+	return obj
+}
+
+// clusterFlags marks adjacent short bool flags which can be clustered as
+// multiple flags (e.g. -al).
+//
+// The given clusters indicate whether each of the flags can be clustered.
+func clusterFlags(flagObjs []FlagObject, clusters []bool) {
+	canCluster := func(i int) bool {
+		obj := flagObjs[i]
+		return clusters[i] && obj.Bool && len(obj.Name) == 2 && !strings.HasPrefix(obj.Name, "--")
+	}
+
+	for i := 0; i < len(flagObjs); {
+		// Find the end of the adjacent flags that can be clustered.
+		j := i
+		for j < len(flagObjs) && canCluster(j) {
+			j++
+		}
+
+		// Mark the start and end if there is more than one flag.
+		if j-i > 1 {
+			flagObjs[i].MultipleFlagsStart = true
+			flagObjs[j-1].MultipleFlagsEnd = true
+		}
+
+		if j == i {
+			j++
+		}
+		i = j
+	}
+}
@@ -0,0 +1,213 @@
+package mojo_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ravernkoh/mojo"
+)
+
+type marshalOptions struct {
+	All     bool              `mojo:"-a,--all,cluster"`
+	Long    bool              `mojo:"-l,cluster"`
+	Level   int               `mojo:"--level,-l,combined"`
+	Timeout time.Duration     `mojo:"--timeout"`
+	Tags    []string          `mojo:"--tag"`
+	Labels  map[string]string `mojo:"--label"`
+
+	Add    *marshalAddOptions `mojo:"add"`
+	Remove *marshalAddOptions `mojo:"remove"`
+}
+
+type marshalAddOptions struct {
+	Force bool `mojo:"--force"`
+}
+
+func TestMarshal(t *testing.T) {
+	type args struct {
+		name string
+		v    interface{}
+	}
+
+	type rets struct {
+		objs mojo.Objects
+		args []string
+		err  error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrInvalidStruct",
+			args: args{
+				name: "ls",
+				v:    marshalOptions{},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid struct"),
+			},
+		},
+		{
+			name: "ErrMultipleCommands",
+			args: args{
+				name: "ls",
+				v: &marshalOptions{
+					Add:    &marshalAddOptions{},
+					Remove: &marshalAddOptions{},
+				},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: multiple commands: Remove"),
+			},
+		},
+		{
+			name: "Zero",
+			args: args{
+				name: "ls",
+				v:    &marshalOptions{},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "ls"},
+				},
+				args: []string{"ls"},
+			},
+		},
+		{
+			name: "Flags",
+			args: args{
+				name: "ls",
+				v: &marshalOptions{
+					All:     true,
+					Long:    true,
+					Level:   5,
+					Timeout: time.Minute,
+					Tags:    []string{"a", "b"},
+					Labels:  map[string]string{"b": "2", "a": "1"},
+				},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "ls"},
+					mojo.FlagObject{Name: "-a", Canonical: "-a", Aliases: []string{"--all"}, Bool: true, MultipleFlagsStart: true},
+					mojo.FlagObject{Name: "-l", Canonical: "-l", Bool: true, MultipleFlagsEnd: true},
+					mojo.FlagObject{Name: "--level", Value: "5", Canonical: "--level", Aliases: []string{"-l"}, CombinedFlagValues: true},
+					mojo.FlagObject{Name: "--timeout", Value: "1m0s", Canonical: "--timeout"},
+					mojo.FlagObject{Name: "--tag", Value: "a", Canonical: "--tag"},
+					mojo.FlagObject{Name: "--tag", Value: "b", Canonical: "--tag"},
+					mojo.FlagObject{Name: "--label", Value: "a=1", Canonical: "--label"},
+					mojo.FlagObject{Name: "--label", Value: "b=2", Canonical: "--label"},
+				},
+				args: []string{"ls", "-al", "--level=5", "--timeout", "1m0s", "--tag", "a", "--tag", "b", "--label", "a=1", "--label", "b=2"},
+			},
+		},
+		{
+			name: "SubcommandAndFlags",
+			args: args{
+				name: "ls",
+				v: &marshalOptions{
+					All: true,
+					Add: &marshalAddOptions{
+						Force: true,
+					},
+				},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "ls"},
+					mojo.FlagObject{Name: "-a", Canonical: "-a", Aliases: []string{"--all"}, Bool: true},
+					mojo.CommandObject{Name: "add"},
+					mojo.FlagObject{Name: "--force", Canonical: "--force", Bool: true},
+				},
+				args: []string{"ls", "-a", "add", "--force"},
+			},
+		},
+		{
+			name: "CountingFlags",
+			args: args{
+				name: "ls",
+				v: &struct {
+					Verbose int `mojo:"-v,count"`
+					Debug   int `mojo:"--debug,-d,count"`
+					Quiet   int `mojo:"-q,count"`
+					Level   int `mojo:"--level"`
+				}{
+					Verbose: 3,
+					Debug:   2,
+					Quiet:   1,
+					Level:   3,
+				},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "ls"},
+					mojo.FlagObject{Name: "-v", Canonical: "-v", Bool: true, Counter: true, MultipleFlagsStart: true},
+					mojo.FlagObject{Name: "-v", Canonical: "-v", Bool: true, Counter: true},
+					mojo.FlagObject{Name: "-v", Canonical: "-v", Bool: true, Counter: true, MultipleFlagsEnd: true},
+					mojo.FlagObject{Name: "--debug", Value: "2", Canonical: "--debug", Aliases: []string{"-d"}, Counter: true, CombinedFlagValues: true},
+					mojo.FlagObject{Name: "-q", Canonical: "-q", Bool: true, Counter: true},
+					mojo.FlagObject{Name: "--level", Value: "3", Canonical: "--level"},
+				},
+				args: []string{"ls", "-vvv", "--debug=2", "-q", "--level", "3"},
+			},
+		},
+		{
+			name: "SubcommandWithoutFlags",
+			args: args{
+				name: "ls",
+				v: &marshalOptions{
+					Add: &marshalAddOptions{},
+				},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "ls"},
+					mojo.CommandObject{Name: "add"},
+				},
+				args: []string{"ls", "add"},
+			},
+		},
+		{
+			name: "ErrUnsupportedType",
+			args: args{
+				name: "git",
+				v: &struct {
+					Add marshalAddOptions `mojo:"add"`
+				}{},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: unsupported type: Add"),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.objs, got.err = mojo.Marshal(test.args.name, test.args.v)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if !reflect.DeepEqual(got.objs, test.want.objs) {
+				t.Errorf("want objs %v, got objs %v", test.want.objs, got.objs)
+				return
+			}
+			if test.want.err != nil {
+				return
+			}
+			got.args, got.err = got.objs.Assemble()
+			if got.err != nil {
+				t.Errorf("want err %v, got err %v", nil, got.err)
+				return
+			}
+			if !reflect.DeepEqual(got.args, test.want.args) {
+				t.Errorf("want args %v, got args %v", test.want.args, got.args)
+			}
+		})
+	}
+}
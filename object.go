@@ -11,20 +11,83 @@ type Object interface {
 // CommandObject represents a command that has been parsed.
 type CommandObject struct {
 	Name string
+
+	// Canonical is the name of the command in the configuration, which
+	// might differ from Name if the command was passed using one of its
+	// aliases or abbreviated.
+	//
+	// It is empty for the root command, which is not checked.
+	Canonical string
+
+	// DoubleDash indicates whether the double dash (i.e. --) was passed to
+	// this command, even if no arguments came after it.
+	//
+	// Check DisallowDoubleDash in Config for more information.
+	DoubleDash bool
 }
 
 func (CommandObject) object() {}
 
+// commandName returns the name of the command in the configuration, or the name
+// it was passed as if it doesn't have one.
+func (obj CommandObject) commandName() string {
+	if obj.Canonical != "" {
+		return obj.Canonical
+	}
+	return obj.Name
+}
+
 // FlagObject represents a flag that has been parsed.
 type FlagObject struct {
 	Name  string
 	Value string
 
+	// Abbreviation is the prefix that this flag was passed as (e.g. --verb
+	// for --verbose), with Name being the name it resolved to.
+	//
+	// Check AllowFlagAbbreviations in Config for more information.
+	Abbreviation string
+
+	// Canonical is the name of the flag in the configuration, which might
+	// differ from Name if the flag was passed using one of its aliases.
+	//
+	// It is empty if the flag wasn't found in the configuration.
+	Canonical string
+
+	// Aliases contains the aliases of the flag in the configuration.
+	//
+	// Check Aliases in FlagConfig for more information.
+	Aliases []string
+
 	// Bool indicates whether this flag was a bool flag.
 	//
 	// This means that the flag was passed without a value.
 	Bool bool
 
+	// Negated indicates whether this flag was passed using its negated
+	// name (e.g. --no-color), which means that its value is false.
+	//
+	// Check Negatable in FlagConfig for more information.
+	Negated bool
+
+	// Counter indicates whether this flag counts the number of times it
+	// is passed.
+	//
+	// Check Count in FlagConfig for more information.
+	Counter bool
+
+	// OptionalValue indicates whether the value of this flag is optional,
+	// which means that its value is always attached to it when assembled
+	// (e.g. --color=always or -calways).
+	//
+	// Check OptionalValue in FlagConfig for more information.
+	OptionalValue bool
+
+	// ImplicitValue indicates whether this flag with an optional value was
+	// passed without a value, which means that its value is the implicit
+	// value in the configuration.
+	ImplicitValue bool
+
 	// MultipleFlagsStart indicates whether this flag was the start of
 	// multiple flags (e.g. ls -al).
 	//
@@ -42,18 +105,119 @@ type FlagObject struct {
 	//
 	// Check DisallowCombinedFlagValues in Config for more information.
 	CombinedFlagValues bool
+
+	// AttachedFlagValue indicates whether the value of this short flag
+	// was attached to it (e.g. -j8 or -abj8).
+	AttachedFlagValue bool
+
+	// Command contains the command stack of the command that this flag
+	// belongs to (e.g. [git remote add]), which is the command configuring
+	// it, or the command it was passed to if it isn't configured.
+	Command []string
+
+	// Config is the configuration of this flag, which is nil if it isn't
+	// configured.
+	//
+	// It points to the configuration given when parsing.
+	Config *FlagConfig
+
+	// Index is the index of the argument that this flag was parsed from,
+	// with the root command at index 0, if Source is SourceArgument.
+	//
+	// Flags parsed from the same argument (e.g. ls -al) share the same
+	// index. It is only set by Parse.
+	Index int
+
+	// Source indicates where the value of this flag came from.
+	//
+	// Flags that weren't parsed from the arguments are not assembled. To
+	// assemble such a flag anyway, set its source to SourceArgument.
+	Source Source
+
+	// File and Line indicate the configuration file and line number that
+	// the value of this flag came from, if Source is SourceFile.
+	File string
+	Line int
 }
 
 func (FlagObject) object() {}
 
+// hasName returns whether the given name is the name, canonical name or one of
+// the aliases of the flag.
+func (obj FlagObject) hasName(name string) bool {
+	if obj.Name == name || obj.Canonical == name {
+		return true
+	}
+	for _, alias := range obj.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Source represents where the value of a flag came from.
+type Source int
+
+// Possible sources.
+const (
+	// SourceArgument indicates that the flag was parsed from the arguments.
+	SourceArgument Source = iota
+
+	// SourceDefault indicates that the flag wasn't passed, and the value is
+	// the default value in the configuration.
+	//
+	// Check Default in FlagConfig for more information.
+	SourceDefault
+
+	// SourceEnv indicates that the flag wasn't passed, and the value is
+	// from an environment variable.
+	//
+	// Check Env in FlagConfig for more information.
+	SourceEnv
+
+	// SourceFile indicates that the flag wasn't passed, and the value is
+	// from a configuration file.
+	//
+	// Check Files in Config for more information.
+	SourceFile
+)
+
+// String returns the name of the source.
+func (s Source) String() string {
+	switch s {
+	case SourceArgument:
+		return "argument"
+	case SourceDefault:
+		return "default"
+	case SourceEnv:
+		return "env"
+	case SourceFile:
+		return "file"
+	}
+	return "unknown"
+}
+
 // ArgumentObject represents an argument that has been parsed.
 type ArgumentObject struct {
 	Value string
+
+	// Name is the name of the argument in the configuration.
+	//
+	// It is empty if the command has no arguments configured. Check
+	// Arguments in CommandConfig for more information.
+	Name string
+
+	// AfterDoubleDash indicates whether this argument came after the
+	// double dash (i.e. --).
+	//
+	// Check DisallowDoubleDash in Config for more information.
+	AfterDoubleDash bool
 }
 
 func (ArgumentObject) object() {}
 
-// ArrayFlag returns the flags with the given name in order.
+// ArrayFlag returns the flags with the given name or alias in order.
 func (objs Objects) ArrayFlag(name string) []FlagObject {
 	var flagObjs []FlagObject
 
@@ -65,7 +229,7 @@ func (objs Objects) ArrayFlag(name string) []FlagObject {
 		}
 
 		// Check if the name is correct and append.
-		if flagObj.Name == name {
+		if flagObj.hasName(name) {
 			flagObjs = append(flagObjs, flagObj)
 		}
 	}
@@ -73,7 +237,7 @@ func (objs Objects) ArrayFlag(name string) []FlagObject {
 	return flagObjs
 }
 
-// Flag returns the first flag with the given name.
+// Flag returns the first flag with the given name or alias.
 //
 // An error will be returned if there are no flags found or if there is more
 // than one flag found.
@@ -94,6 +258,41 @@ func (objs Objects) Flag(name string) (FlagObject, error) {
 	return flagObjs[0], nil
 }
 
+// CommandFlags returns the flags belonging to the command at the given depth
+// of the command stack in order, with the root command at depth 0.
+//
+// Check Command in FlagObject for more information.
+func (objs Objects) CommandFlags(depth int) []FlagObject {
+	var flagObjs []FlagObject
+
+	for _, obj := range objs {
+		flagObj, ok := obj.(FlagObject)
+		if ok && len(flagObj.Command) == depth+1 {
+			flagObjs = append(flagObjs, flagObj)
+		}
+	}
+
+	return flagObjs
+}
+
+// CommandArguments returns the arguments passed to the command at the given
+// depth of the command stack in order, with the root command at depth 0.
+func (objs Objects) CommandArguments(depth int) []ArgumentObject {
+	var argObjs []ArgumentObject
+
+	segs := commandSegments(objs)
+	if depth < 0 || depth >= len(segs) {
+		return nil
+	}
+	for _, obj := range segs[depth] {
+		if argObj, ok := obj.(ArgumentObject); ok {
+			argObjs = append(argObjs, argObj)
+		}
+	}
+
+	return argObjs
+}
+
 // Argument returns the argument at the given index.
 func (objs Objects) Argument(i int) (ArgumentObject, error) {
 	var j int
@@ -116,3 +315,38 @@ func (objs Objects) Argument(i int) (ArgumentObject, error) {
 		Err:   ErrArgumentNotFound,
 	}
 }
+
+// ArrayArgument returns the arguments with the given name in order.
+func (objs Objects) ArrayArgument(name string) []ArgumentObject {
+	var argObjs []ArgumentObject
+
+	for _, obj := range objs {
+		argObj, ok := obj.(ArgumentObject)
+		if ok && argObj.Name == name {
+			argObjs = append(argObjs, argObj)
+		}
+	}
+
+	return argObjs
+}
+
+// NamedArgument returns the argument with the given name.
+//
+// An error will be returned if there are no arguments found or if there is
+// more than one argument found.
+func (objs Objects) NamedArgument(name string) (ArgumentObject, error) {
+	argObjs := objs.ArrayArgument(name)
+	if len(argObjs) == 0 {
+		return ArgumentObject{}, ArgumentError{
+			Name: name,
+			Err:  ErrArgumentNotFound,
+		}
+	}
+	if len(argObjs) > 1 {
+		return ArgumentObject{}, ArgumentError{
+			Name: name,
+			Err:  ErrUnexpectedArrayArgument,
+		}
+	}
+	return argObjs[0], nil
+}
@@ -66,6 +66,23 @@ func TestObjects_Flags(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Aliases",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "-v", Canonical: "--verbose", Aliases: []string{"-v", "-V"}, Bool: true},
+					mojo.FlagObject{Name: "--verbose", Canonical: "--verbose", Aliases: []string{"-v", "-V"}, Bool: true},
+				},
+				name: "-V",
+			},
+			want: rets{
+				objs: []mojo.FlagObject{
+					{Name: "-v", Canonical: "--verbose", Aliases: []string{"-v", "-V"}, Bool: true},
+					{Name: "--verbose", Canonical: "--verbose", Aliases: []string{"-v", "-V"}, Bool: true},
+				},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -134,6 +151,19 @@ func TestObjects_Flag(t *testing.T) {
 				obj: mojo.FlagObject{Name: "--verbose", Bool: true},
 			},
 		},
+		{
+			name: "Canonical",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "-v", Canonical: "--verbose", Aliases: []string{"-v"}, Bool: true},
+				},
+				name: "--verbose",
+			},
+			want: rets{
+				obj: mojo.FlagObject{Name: "-v", Canonical: "--verbose", Aliases: []string{"-v"}, Bool: true},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -150,6 +180,85 @@ func TestObjects_Flag(t *testing.T) {
 	}
 }
 
+func TestObjects_CommandFlags(t *testing.T) {
+	objs := mojo.Objects{
+		mojo.CommandObject{Name: "tool"},
+		mojo.FlagObject{Name: "--verbose", Bool: true, Command: []string{"tool"}},
+		mojo.ArgumentObject{Value: "a"},
+		mojo.CommandObject{Name: "sub", Canonical: "sub"},
+		mojo.FlagObject{Name: "--level", Value: "1", Command: []string{"tool"}},
+		mojo.FlagObject{Name: "--level", Value: "2", Command: []string{"tool", "sub"}},
+		mojo.ArgumentObject{Value: "b"},
+		mojo.ArgumentObject{Value: "c"},
+	}
+
+	type args struct {
+		depth int
+	}
+
+	type rets struct {
+		flagObjs []mojo.FlagObject
+		argObjs  []mojo.ArgumentObject
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "Root",
+			args: args{
+				depth: 0,
+			},
+			want: rets{
+				flagObjs: []mojo.FlagObject{
+					{Name: "--verbose", Bool: true, Command: []string{"tool"}},
+					{Name: "--level", Value: "1", Command: []string{"tool"}},
+				},
+				argObjs: []mojo.ArgumentObject{
+					{Value: "a"},
+				},
+			},
+		},
+		{
+			name: "Subcommand",
+			args: args{
+				depth: 1,
+			},
+			want: rets{
+				flagObjs: []mojo.FlagObject{
+					{Name: "--level", Value: "2", Command: []string{"tool", "sub"}},
+				},
+				argObjs: []mojo.ArgumentObject{
+					{Value: "b"},
+					{Value: "c"},
+				},
+			},
+		},
+		{
+			name: "OutOfRange",
+			args: args{
+				depth: 2,
+			},
+			want: rets{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.flagObjs = objs.CommandFlags(test.args.depth)
+			got.argObjs = objs.CommandArguments(test.args.depth)
+			if !reflect.DeepEqual(got.flagObjs, test.want.flagObjs) {
+				t.Errorf("want flags %v, got flags %v", test.want.flagObjs, got.flagObjs)
+			}
+			if !reflect.DeepEqual(got.argObjs, test.want.argObjs) {
+				t.Errorf("want arguments %v, got arguments %v", test.want.argObjs, got.argObjs)
+			}
+		})
+	}
+}
+
 func TestObjects_Argument(t *testing.T) {
 	type args struct {
 		objs mojo.Objects
@@ -209,3 +318,77 @@ func TestObjects_Argument(t *testing.T) {
 		})
 	}
 }
+
+func TestObjects_NamedArgument(t *testing.T) {
+	type args struct {
+		objs mojo.Objects
+		name string
+	}
+
+	type rets struct {
+		obj mojo.ArgumentObject
+		err error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrArgumentNotFound",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "cp"},
+					mojo.ArgumentObject{Value: "a.txt"},
+				},
+				name: "SRC",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: argument not found: SRC"),
+			},
+		},
+		{
+			name: "ErrUnexpectedArrayArgument",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "cp"},
+					mojo.ArgumentObject{Value: "a.txt", Name: "SRC"},
+					mojo.ArgumentObject{Value: "b.txt", Name: "SRC"},
+					mojo.ArgumentObject{Value: "c", Name: "DST"},
+				},
+				name: "SRC",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: unexpected array argument: SRC"),
+			},
+		},
+		{
+			name: "Argument",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "cp"},
+					mojo.ArgumentObject{Value: "a.txt", Name: "SRC"},
+					mojo.ArgumentObject{Value: "c", Name: "DST"},
+				},
+				name: "DST",
+			},
+			want: rets{
+				obj: mojo.ArgumentObject{Value: "c", Name: "DST"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.obj, got.err = test.args.objs.NamedArgument(test.args.name)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if !reflect.DeepEqual(got.obj, test.want.obj) {
+				t.Errorf("want obj %v, got obj %v", test.want.obj, got.obj)
+			}
+		})
+	}
+}
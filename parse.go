@@ -1,6 +1,7 @@
 package mojo
 
 import (
+	"strconv"
 	"strings"
 )
 
@@ -11,30 +12,57 @@ func Parse(conf Config, args []string) (Objects, error) {
 	if len(args) < 1 {
 		panic("runtime error: index out of bounds")
 	}
-	return parseCommand(conf, []string{}, args)
+
+	objs, err := parseCommand(conf, []string{}, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	objs, err = parseArguments(conf, objs)
+	if err != nil {
+		return nil, err
+	}
+	objs, err = parseMissingFlags(conf, objs)
+	if err != nil {
+		return nil, err
+	}
+	if err := parseValues(conf, objs); err != nil {
+		return nil, err
+	}
+	if err := parseConstraints(conf, objs); err != nil {
+		return nil, err
+	}
+	return objs, nil
 }
 
 // parseCommand parses the given arguments into objects using the given
 // configuration, in the context of the current command stack.
 //
 // The first argument given should be the name of the root command (e.g. git).
-// Note that the first argument is not checked.
-func parseCommand(conf Config, commands []string, args []string) ([]Object, error) {
+// Note that the first argument is not checked. The given index is the index of
+// the first argument in the arguments given to Parse.
+func parseCommand(conf Config, commands []string, args []string, index int) ([]Object, error) {
 	var objs []Object
 
-	// Append the root command to the objects and the command stack.
-	objs = append(objs, CommandObject{Name: args[0]})
-	commands = append(commands, args[0])
+	// Append the command to the objects and the command stack, using the
+	// canonical name of the command if it isn't the root command.
+	obj := CommandObject{Name: args[0]}
+	if len(commands) > 0 {
+		cmd, _ := configCommand(conf, commands, args[0])
+		obj.Canonical = cmd.Name
+	}
+	objs = append(objs, obj)
+	commands = append(commands, obj.commandName())
 	args = args[1:]
+	index++
 
 	// Go through the rest of the arguments.
 	for len(args) > 0 {
 		// Determine if the argument is a command or argument.
-		if !strings.HasPrefix(args[0], "-") {
+		if !isFlag(conf, commands, args[0]) {
 			// Check for command.
-			if _, ok := configCommands(conf, commands)[0].Command(args[0]); ok {
+			if _, ok := configCommand(conf, commands, args[0]); ok {
 				// Parse the subcommand.
-				subobjs, err := parseCommand(conf, commands, args)
+				subobjs, err := parseCommand(conf, commands, args, index)
 				if err != nil {
 					return nil, err
 				}
@@ -45,23 +73,39 @@ func parseCommand(conf Config, commands []string, args []string) ([]Object, erro
 				break
 			}
 
+			// Check for unconfigured command if it is the first
+			// argument of a command that has subcommands and no
+			// configured arguments.
+			if cmd := configCommands(conf, commands)[0]; conf.DisallowUnconfiguredCommands && !hasArguments(objs) && len(cmd.Commands) > 0 && len(cmd.Arguments) == 0 {
+				return nil, CommandError{
+					Name:        args[0],
+					Err:         ErrCommandNotFound,
+					Suggestions: suggestions(args[0], cmd.subcommandNames()),
+				}
+			}
+
 			// Append as argument.
 			objs = append(objs, ArgumentObject{Value: args[0]})
 			args = args[1:]
+			index++
 			continue
 		}
 
 		// Check for the double dash only.
 		if args[0] == "--" {
-			obj, err := parseDoubleDash(conf)
+			argObjs, err := parseDoubleDash(conf, args[1:])
 			if err != nil {
 				return nil, err
 			}
 
-			// Append the double dash.
-			objs = append(objs, obj)
-			args = args[1:]
-			continue
+			// Append everything and break, since everything after
+			// the double dash has been parsed.
+			obj.DoubleDash = true
+			objs[0] = obj
+			for _, obj := range argObjs {
+				objs = append(objs, obj)
+			}
+			break
 		}
 
 		// Parse as flag.
@@ -70,26 +114,339 @@ func parseCommand(conf Config, commands []string, args []string) ([]Object, erro
 			return nil, err
 		}
 		for _, obj := range flagObjs {
+			obj.Config, obj.Command = lookupFlag(conf, commands, obj.Name)
+			if obj.Command == nil {
+				obj.Command = append([]string(nil), commands...)
+			}
+			obj.Index = index
 			objs = append(objs, obj)
 		}
 		args = args[n:]
+		index += n
 	}
 
 	return objs, nil
 }
 
-// parseDoubleDash parses a double dash argument based on the given
+// hasArguments returns whether the given objects contain an argument.
+func hasArguments(objs []Object) bool {
+	for _, obj := range objs {
+		if _, ok := obj.(ArgumentObject); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseArguments checks the number of arguments passed to each command in the
+// given objects and sets their names, for the commands with arguments
+// configured.
+func parseArguments(conf Config, objs []Object) ([]Object, error) {
+	var (
+		newObjs  []Object
+		commands []string
+		index    int
+	)
+
+	for _, seg := range commandSegments(objs) {
+		commands = append(commands, seg[0].(CommandObject).commandName())
+
+		var argIndexes []int
+		for i, obj := range seg {
+			if _, ok := obj.(ArgumentObject); ok {
+				argIndexes = append(argIndexes, i)
+			}
+		}
+
+		if cmd := configCommands(conf, commands)[0]; len(cmd.Arguments) > 0 {
+			names, err := argumentNames(cmd.Arguments, index, len(argIndexes))
+			if err != nil {
+				return nil, err
+			}
+			for i, j := range argIndexes {
+				argObj := seg[j].(ArgumentObject)
+				argObj.Name = names[i]
+				seg[j] = argObj
+			}
+		}
+
+		index += len(argIndexes)
+		newObjs = append(newObjs, seg...)
+	}
+
+	return newObjs, nil
+}
+
+// argumentNames returns the names of the given number of arguments passed to
+// a command with the given arguments configured, with the first argument
+// having the given index.
+//
+// Each argument is first given the minimum number of values, then the rest of
+// the values are given to the earliest arguments that can take them.
+func argumentNames(args []ArgumentConfig, index int, n int) ([]string, error) {
+	var (
+		counts = make([]int, len(args))
+		rest   = n
+	)
+
+	for i, arg := range args {
+		min, _ := arg.arity()
+		if rest < min {
+			return nil, ArgumentError{
+				Index: index + n,
+				Name:  arg.Name,
+				Err:   ErrTooFewArguments,
+			}
+		}
+		counts[i] = min
+		rest -= min
+	}
+
+	for i, arg := range args {
+		min, max := arg.arity()
+		count := rest
+		if max != -1 && max-min < count {
+			count = max - min
+		}
+		counts[i] += count
+		rest -= count
+	}
+
+	if rest > 0 {
+		return nil, ArgumentError{
+			Index: index + n - rest,
+			Err:   ErrTooManyArguments,
+		}
+	}
+
+	var names []string
+	for i, arg := range args {
+		for j := 0; j < counts[i]; j++ {
+			names = append(names, arg.Name)
+		}
+	}
+	return names, nil
+}
+
+// parseMissingFlags adds flags with values from the environment, configuration
+// files or default values for the flags that weren't parsed, and checks that
+// all required flags were parsed, for each command in the given objects.
+//
+// The added flags are placed at the end of the objects of the command they are
+// configured in.
+func parseMissingFlags(conf Config, objs []Object) ([]Object, error) {
+	var (
+		newObjs  []Object
+		commands []string
+	)
+
+	segs := commandSegments(objs)
+	for i, seg := range segs {
+		commands = append(commands, seg[0].(CommandObject).commandName())
+
+		// Flags can be passed in the command or any of its subcommands.
+		var rest []Object
+		for _, seg := range segs[i:] {
+			rest = append(rest, seg...)
+		}
+
+		cmd := configCommands(conf, commands)[0]
+		for i := range cmd.Flags {
+			flag := &cmd.Flags[i]
+
+			// Local flags can only be passed in the command.
+			passed := rest
+			if flag.Local {
+				passed = seg
+			}
+			if hasFlagObject(conf, passed, flag) {
+				continue
+			}
+
+			if value, ok := conf.lookupEnv(*flag); ok {
+				seg = append(seg, FlagObject{
+					Name:      flag.Name,
+					Value:     value,
+					Canonical: flag.Name,
+					Aliases:   flag.Aliases,
+					Command:   append([]string(nil), commands...),
+					Config:    flag,
+					Source:    SourceEnv,
+				})
+				continue
+			}
+
+			if flagObjs := conf.lookupFiles(commands, *flag); len(flagObjs) > 0 {
+				for _, flagObj := range flagObjs {
+					flagObj.Command = append([]string(nil), commands...)
+					flagObj.Config = flag
+					seg = append(seg, flagObj)
+				}
+				continue
+			}
+
+			if flag.Default != "" {
+				seg = append(seg, FlagObject{
+					Name:      flag.Name,
+					Value:     flag.Default,
+					Canonical: flag.Name,
+					Aliases:   flag.Aliases,
+					Command:   append([]string(nil), commands...),
+					Config:    flag,
+					Source:    SourceDefault,
+				})
+				continue
+			}
+
+			if flag.Required {
+				return nil, FlagError{
+					Name: flag.Name,
+					Err:  ErrRequiredFlag,
+				}
+			}
+		}
+
+		newObjs = append(newObjs, seg...)
+	}
+
+	return newObjs, nil
+}
+
+// parseValues checks the values of the flags in the given objects against their
+// configuration.
+//
+// Check Choices and Validate in FlagConfig for more information.
+func parseValues(conf Config, objs []Object) error {
+	for _, obj := range objs {
+		switch obj := obj.(type) {
+		case FlagObject:
+			if obj.Config == nil {
+				continue
+			}
+			if err := validateFlag(*obj.Config, obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseConstraints checks that the flags in the given objects satisfy the
+// constraints of each command in the given objects.
+func parseConstraints(conf Config, objs []Object) error {
+	var commands []string
+	for _, obj := range objs {
+		cmdObj, ok := obj.(CommandObject)
+		if !ok {
+			continue
+		}
+		commands = append(commands, cmdObj.commandName())
+
+		for _, constraint := range configCommands(conf, commands)[0].Constraints {
+			if err := checkConstraint(conf, commands, constraint, objs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkConstraint checks that the flags in the given objects satisfy the given
+// constraint of the command at the top of the given command stack.
+func checkConstraint(conf Config, commands []string, constraint FlagConstraint, objs []Object) error {
+	var passed, missing []string
+	for i, name := range constraint.Flags {
+		// Use the canonical name in case an alias was given.
+		if flag, ok := configFlag(conf, commands, name); ok {
+			name = flag.Name
+		}
+
+		if hasPassedFlagObject(objs, name) {
+			passed = append(passed, name)
+		} else if i == 0 && constraint.Kind == Requires {
+			// Nothing is required if the first flag isn't passed.
+			return nil
+		} else {
+			missing = append(missing, name)
+		}
+	}
+
+	switch constraint.Kind {
+	case ExactlyOne:
+		if len(passed) == 0 {
+			return ConstraintError{Names: missing, Err: ErrMissingOneOfFlags}
+		}
+		fallthrough
+	case AtMostOne:
+		if len(passed) > 1 {
+			return ConstraintError{Names: passed, Err: ErrConflictingFlags}
+		}
+	case AllOrNone:
+		if len(passed) > 0 && len(missing) > 0 {
+			return ConstraintError{Names: missing, Err: ErrIncompleteFlags}
+		}
+	case Requires:
+		if len(missing) > 0 {
+			return ConstraintError{Names: missing, Flag: passed[0], Err: ErrMissingRequiredFlags}
+		}
+	}
+	return nil
+}
+
+// hasPassedFlagObject returns whether there is a flag with the given name in
+// the given objects which was parsed from the arguments.
+func hasPassedFlagObject(objs []Object, name string) bool {
+	for _, obj := range objs {
+		if flagObj, ok := obj.(FlagObject); ok && flagObj.Source == SourceArgument && flagObj.hasName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFlagObject returns whether there is a flag configured by the given flag
+// configuration in the given objects.
+//
+// Flags of subcommands with the same name as the given flag hide it, so they
+// are also considered to be the given flag.
+func hasFlagObject(conf Config, objs []Object, flag *FlagConfig) bool {
+	for _, obj := range objs {
+		flagObj, ok := obj.(FlagObject)
+		if !ok || flagObj.Config == nil {
+			continue
+		}
+		if flagObj.Config == flag {
+			return true
+		}
+		if hidden, _ := lookupFlag(conf, flagObj.Command, flag.Name); hidden == flagObj.Config {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDoubleDash parses the arguments after a double dash based on the given
 // configuration.
-func parseDoubleDash(conf Config) (Object, error) {
+//
+// The given arguments should not include the double dash itself. All of them
+// are parsed as arguments, regardless of whether they look like flags or
+// commands.
+func parseDoubleDash(conf Config, args []string) ([]ArgumentObject, error) {
 	if conf.DisallowDoubleDash {
-		return ArgumentObject{}, FlagError{
+		return nil, FlagError{
 			Name: "--",
 			Err:  ErrInvalidFlag,
 		}
 	}
-	return ArgumentObject{
-		Value: "--",
-	}, nil
+
+	var objs []ArgumentObject
+	for _, arg := range args {
+		objs = append(objs, ArgumentObject{
+			Value:           arg,
+			AfterDoubleDash: true,
+		})
+	}
+	return objs, nil
 }
 
 // parseFlag parses a flag from the given arguments based on the given
@@ -100,6 +457,11 @@ func parseFlag(conf Config, commands []string, args []string) ([]FlagObject, int
 		n    = 1
 	)
 
+	// Check for short flag with attached value (e.g. -j8).
+	if attachedObjs, ok, err := parseAttachedFlag(conf, commands, args[0]); ok || err != nil {
+		return attachedObjs, 1, err
+	}
+
 	// Check for combined flag value and splits it into two arguments if
 	// found.
 	var combinedFlagValue bool
@@ -115,6 +477,20 @@ func parseFlag(conf Config, commands []string, args []string) ([]FlagObject, int
 		n--
 	}
 
+	// Check for abbreviated long flag and replaces it with the name it
+	// resolves to.
+	var abbreviation string
+	if conf.AllowFlagAbbreviations {
+		name, err := resolveAbbreviation(conf, commands, args[0])
+		if err != nil {
+			return nil, 0, err
+		}
+		if name != args[0] {
+			abbreviation = args[0]
+			args = append([]string{name}, args[1:]...)
+		}
+	}
+
 	// Check for single dash flag with multiple characters and removes all
 	// the bool flags, leaving only the last flag which possibly has a
 	// value.
@@ -162,10 +538,13 @@ func parseFlag(conf Config, commands []string, args []string) ([]FlagObject, int
 		err error
 	)
 
-	// If there is a next value, and it isn't a flag, then create
-	// a flag with a value. Otherwise, create the flag as a bool
-	// flag.
-	if len(args) > 1 && !strings.HasPrefix(args[1], "-") {
+	// If the value was combined, then always create a flag with the
+	// value. If there is a next value, and it isn't a flag or the flag
+	// is defined to take a value in the configuration, then create a flag
+	// with a value. Otherwise, create the flag as a bool flag.
+	if combinedFlagValue {
+		obj, err = newCombinedFlag(conf, commands, args[0], args[1])
+	} else if len(args) > 1 && (!isFlag(conf, commands, args[1]) || takesValue(conf, commands, args[0])) {
 		obj, err = newFlag(conf, commands, args[0], args[1])
 	} else {
 		obj, err = newBoolFlag(conf, commands, args[0])
@@ -174,17 +553,158 @@ func parseFlag(conf Config, commands []string, args []string) ([]FlagObject, int
 		return nil, 0, err
 	}
 
+	obj.Abbreviation = abbreviation
 	obj.CombinedFlagValues = combinedFlagValue
 	obj.MultipleFlagsEnd = mutlipleFlagsEnd
 
 	objs = append(objs, obj)
-	if !obj.Bool {
+	if !obj.Bool && !obj.ImplicitValue {
 		n++
 	}
 
 	return objs, n, nil
 }
 
+// isFlag returns whether the given argument is a flag based on the given
+// configuration.
+//
+// Check AllowNegativeNumbers in Config for more information.
+func isFlag(conf Config, commands []string, arg string) bool {
+	if !strings.HasPrefix(arg, "-") {
+		return false
+	}
+	if conf.AllowNegativeNumbers && isNegativeNumber(arg) {
+		_, ok := configFlag(conf, commands, arg)
+		return ok
+	}
+	return true
+}
+
+// isNegativeNumber returns whether the given argument is a negative number
+// (e.g. -5 or -1.5).
+func isNegativeNumber(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' || (arg[1] < '0' || arg[1] > '9') && arg[1] != '.' {
+		return false
+	}
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
+
+// takesValue returns whether the flag with the given name is defined to take
+// the next argument as its value in the configuration.
+func takesValue(conf Config, commands []string, name string) bool {
+	flagConf, ok := configFlag(conf, commands, name)
+	return ok && !flagConf.Bool && !flagConf.OptionalValue
+}
+
+// parseAttachedFlag parses a short flag with its value attached (e.g. -j8) from
+// the given argument based on the given configuration, returning false if the
+// argument isn't one.
+//
+// If multiple flags are allowed, the flag can come after multiple bool flags
+// (e.g. -abj8), with the first flag that takes a value in the configuration
+// taking the rest of the argument as its value.
+func parseAttachedFlag(conf Config, commands []string, arg string) ([]FlagObject, bool, error) {
+	if strings.HasPrefix(arg, "--") || len(arg) <= 2 {
+		return nil, false, nil
+	}
+
+	// Flags in the configuration with such names are parsed as they are.
+	if _, ok := configFlag(conf, commands, arg); ok {
+		return nil, false, nil
+	}
+
+	for i := 1; i < len(arg)-1; i++ {
+		// Only the first flag is checked if multiple flags aren't
+		// allowed.
+		if i > 1 && !conf.AllowMutipleFlags {
+			break
+		}
+
+		name := "-" + arg[i:i+1]
+		flagConf, ok := configFlag(conf, commands, name)
+		if !ok || flagConf.Bool {
+			continue
+		}
+
+		// Leave combined flag values (e.g. -j=8) to be parsed as
+		// usual.
+		value := arg[i+1:]
+		if strings.HasPrefix(value, "=") {
+			return nil, false, nil
+		}
+
+		// Add the flags before as bool flags.
+		var objs []FlagObject
+		for j := 1; j < i; j++ {
+			obj, err := newBoolFlag(conf, commands, "-"+arg[j:j+1])
+			if err != nil {
+				return nil, false, err
+			}
+			objs = append(objs, obj)
+		}
+
+		obj := FlagObject{
+			Name:              name,
+			Value:             value,
+			Canonical:         flagConf.Name,
+			Aliases:           flagConf.Aliases,
+			OptionalValue:     flagConf.OptionalValue,
+			AttachedFlagValue: true,
+		}
+		if len(objs) > 0 {
+			objs[0].MultipleFlagsStart = true
+			obj.MultipleFlagsEnd = true
+		}
+
+		return append(objs, obj), true, nil
+	}
+
+	return nil, false, nil
+}
+
+// resolveAbbreviation returns the name of the long flag that the given name is
+// a unique prefix of, or the given name if it doesn't match any flag.
+//
+// Check AllowFlagAbbreviations in Config for more information.
+func resolveAbbreviation(conf Config, commands []string, name string) (string, error) {
+	if !strings.HasPrefix(name, "--") || len(name) <= 2 {
+		return name, nil
+	}
+
+	// Flags in the configuration with such names are parsed as they are.
+	if _, ok := configFlag(conf, commands, name); ok {
+		return name, nil
+	}
+
+	// Find the matching names, keeping only the first of each flag and
+	// negated flag since they resolve to the same flag.
+	var matches []string
+	for _, flag := range visibleFlags(conf, commands) {
+		for _, names := range [][]string{flag.names(), flag.negatedNames()} {
+			for _, flagName := range names {
+				if strings.HasPrefix(flagName, "--") && strings.HasPrefix(flagName, name) {
+					matches = append(matches, flagName)
+					break
+				}
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return name, nil
+	case 1:
+		return matches[0], nil
+	default:
+		return "", FlagError{
+			Name:        name,
+			Err:         ErrAmbiguousFlag,
+			Suggestions: matches,
+		}
+	}
+}
+
 // newFlag creates a new flag with the given name and value based on the given
 // configuration.
 //
@@ -196,26 +716,58 @@ func newFlag(conf Config, commands []string, name string, value string) (FlagObj
 	// found and unconfigured flags are not allowed, then return error.
 	flagConf, ok := configFlag(conf, commands, name)
 	if conf.DisallowUnconfiguredFlags && !ok {
-		return FlagObject{}, FlagError{
-			Name: name,
-			Err:  ErrUnconfiguredFlag,
-		}
+		return FlagObject{}, unconfiguredFlagError(conf, commands, name)
 	}
 
-	// Create the flag. If the flag is defined to be a bool flag in the
-	// configuration, then don't use the value.
+	// Create the flag. If the flag is defined to be a bool flag or a flag
+	// with an optional value in the configuration, then don't use the
+	// value.
+	if ok && flagConf.OptionalValue {
+		return newImplicitFlag(flagConf, name), nil
+	}
 	if ok && flagConf.Bool {
 		return FlagObject{
-			Name: name,
-			Bool: true,
+			Name:      name,
+			Canonical: flagConf.Name,
+			Aliases:   flagConf.Aliases,
+			Bool:      true,
+			Negated:   flagConf.hasNegatedName(name),
+			Counter:   flagConf.Count,
 		}, nil
 	}
 	return FlagObject{
-		Name:  name,
-		Value: value,
+		Name:      name,
+		Value:     value,
+		Canonical: flagConf.Name,
+		Aliases:   flagConf.Aliases,
 	}, nil
 }
 
+// newCombinedFlag creates a new flag with the given name and value, which were
+// combined (i.e. --flag=value), based on the given configuration.
+//
+// Unlike newFlag, the given value is always used, even if the flag is
+// specified in the configuration to be a bool flag (e.g. --verbose=false).
+func newCombinedFlag(conf Config, commands []string, name string, value string) (FlagObject, error) {
+	obj, err := newFlag(conf, commands, name, value)
+	if err != nil {
+		return FlagObject{}, err
+	}
+
+	// Negated flags already have a value.
+	if obj.Negated {
+		return FlagObject{}, FlagError{
+			Name: name,
+			Err:  ErrInvalidFlag,
+		}
+	}
+
+	obj.Value = value
+	obj.Bool = false
+	obj.ImplicitValue = false
+	return obj, nil
+}
+
 // newBoolFlag creates a new bool flag with the given name based on the given
 // configuration.
 func newBoolFlag(conf Config, commands []string, name string) (FlagObject, error) {
@@ -223,10 +775,12 @@ func newBoolFlag(conf Config, commands []string, name string) (FlagObject, error
 	// found and unconfigured flags are not allowed, then return error.
 	flagConf, ok := configFlag(conf, commands, name)
 	if conf.DisallowUnconfiguredFlags && !ok {
-		return FlagObject{}, FlagError{
-			Name: name,
-			Err:  ErrUnconfiguredFlag,
-		}
+		return FlagObject{}, unconfiguredFlagError(conf, commands, name)
+	}
+
+	// Flags with optional values can be passed without values.
+	if ok && flagConf.OptionalValue {
+		return newImplicitFlag(flagConf, name), nil
 	}
 
 	// If the flag is a not bool flag, then return error.
@@ -239,11 +793,52 @@ func newBoolFlag(conf Config, commands []string, name string) (FlagObject, error
 
 	// Create the flag.
 	return FlagObject{
-		Name: name,
-		Bool: true,
+		Name:      name,
+		Canonical: flagConf.Name,
+		Aliases:   flagConf.Aliases,
+		Bool:      true,
+		Negated:   flagConf.hasNegatedName(name),
+		Counter:   flagConf.Count,
 	}, nil
 }
 
+// newImplicitFlag creates a new flag with the given name and an optional value
+// which was passed without a value, based on the given flag configuration.
+func newImplicitFlag(flagConf FlagConfig, name string) FlagObject {
+	return FlagObject{
+		Name:          name,
+		Value:         flagConf.Implicit,
+		Canonical:     flagConf.Name,
+		Aliases:       flagConf.Aliases,
+		OptionalValue: true,
+		ImplicitValue: true,
+	}
+}
+
+// unconfiguredFlagError returns the error for when the flag with the given name
+// isn't in the configuration, suggesting the closest flags that can be used in
+// the command at the top of the given command stack.
+//
+// Short flags (e.g. -x) have no suggestions, since all other short flags would
+// be close enough to be suggested.
+func unconfiguredFlagError(conf Config, commands []string, name string) error {
+	err := FlagError{
+		Name: name,
+		Err:  ErrUnconfiguredFlag,
+	}
+	if len(name) <= 2 {
+		return err
+	}
+
+	var names []string
+	for _, flag := range visibleFlags(conf, commands) {
+		names = append(names, flag.names()...)
+		names = append(names, flag.negatedNames()...)
+	}
+	err.Suggestions = suggestions(name, names)
+	return err
+}
+
 // configCommands returns the command configurations of the given command stack,
 // with the root command being last.
 //
@@ -259,14 +854,85 @@ func configCommands(conf Config, commands []string) []CommandConfig {
 	return cmds
 }
 
+// configCommand returns the configuration of the subcommand with the given name
+// or alias of the command at the top of the given command stack.
+//
+// Check AllowCommandAbbreviations in Config for more information.
+func configCommand(conf Config, commands []string, name string) (CommandConfig, bool) {
+	cmd := configCommands(conf, commands)[0]
+	if subcmd, ok := cmd.Command(name); ok {
+		return subcmd, true
+	}
+	if !conf.AllowCommandAbbreviations || name == "" {
+		return CommandConfig{}, false
+	}
+
+	// Find the commands with a name or alias that the name is a prefix
+	// of.
+	var matches []CommandConfig
+	for _, subcmd := range cmd.Commands {
+		for _, subcmdName := range subcmd.names() {
+			if strings.HasPrefix(subcmdName, name) {
+				matches = append(matches, subcmd)
+				break
+			}
+		}
+	}
+	if len(matches) != 1 {
+		return CommandConfig{}, false
+	}
+	return matches[0], true
+}
+
 // configFlag returns the flag configuration of the flag with the given name,
 // with precedence given to configuration in the subcommands.
+//
+// Local flags of the commands below the top of the command stack are ignored.
 func configFlag(conf Config, commands []string, name string) (FlagConfig, bool) {
+	flag, _ := lookupFlag(conf, commands, name)
+	if flag == nil {
+		return FlagConfig{}, false
+	}
+	return *flag, true
+}
+
+// lookupFlag returns the flag configuration of the flag with the given name,
+// along with the command stack of the command configuring it.
+//
+// Check configFlag for more information.
+func lookupFlag(conf Config, commands []string, name string) (*FlagConfig, []string) {
 	cmds := configCommands(conf, commands)
-	for _, cmd := range cmds {
-		if flag, ok := cmd.Flag(name); ok {
-			return flag, ok
+	for i, cmd := range cmds {
+		for j := range cmd.Flags {
+			flag := &cmd.Flags[j]
+			if !flag.hasName(name) && !flag.hasNegatedName(name) {
+				continue
+			}
+			if i > 0 && flag.Local {
+				break
+			}
+			return flag, append([]string(nil), commands[:len(commands)-i]...)
+		}
+	}
+	return nil, nil
+}
+
+// visibleFlags returns the flag configurations that can be used in the command
+// at the top of the given command stack, with precedence given to
+// configuration in the subcommands.
+func visibleFlags(conf Config, commands []string) []FlagConfig {
+	var (
+		flags []FlagConfig
+		seen  = make(map[string]bool)
+	)
+	for i, cmd := range configCommands(conf, commands) {
+		for _, flag := range cmd.Flags {
+			if seen[flag.Name] || i > 0 && flag.Local {
+				continue
+			}
+			seen[flag.Name] = true
+			flags = append(flags, flag)
 		}
 	}
-	return FlagConfig{}, false
+	return flags
 }
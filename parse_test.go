@@ -19,6 +19,12 @@ func TestParse(t *testing.T) {
 		err  error
 	}
 
+	// Flags with validation functions can only be compared by pointer.
+	validFlags := []mojo.FlagConfig{
+		{Name: "--format", Choices: []string{"json", "yaml", "text"}},
+		{Name: "--level", Validate: mojo.ValidateRange(1, 5)},
+	}
+
 	tests := []struct {
 		name string
 		args args
@@ -111,7 +117,7 @@ func TestParse(t *testing.T) {
 			want: rets{
 				objs: []mojo.Object{
 					mojo.CommandObject{Name: "tldr"},
-					mojo.FlagObject{Name: "--verbose", Bool: true},
+					mojo.FlagObject{Name: "--verbose", Canonical: "--verbose", Bool: true, Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--verbose", Bool: true}, Index: 1},
 					mojo.ArgumentObject{Value: "nmap"},
 				},
 			},
@@ -133,7 +139,7 @@ func TestParse(t *testing.T) {
 			want: rets{
 				objs: []mojo.Object{
 					mojo.CommandObject{Name: "tldr"},
-					mojo.FlagObject{Name: "--level", Value: "5"},
+					mojo.FlagObject{Name: "--level", Value: "5", Canonical: "--level", Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--level"}, Index: 1},
 					mojo.ArgumentObject{Value: "nmap"},
 				},
 			},
@@ -152,9 +158,9 @@ func TestParse(t *testing.T) {
 			want: rets{
 				objs: []mojo.Object{
 					mojo.CommandObject{Name: "tldr"},
-					mojo.FlagObject{Name: "-v", Bool: true, MultipleFlagsStart: true},
-					mojo.FlagObject{Name: "-b", Bool: true},
-					mojo.FlagObject{Name: "-l", Value: "5", MultipleFlagsEnd: true},
+					mojo.FlagObject{Name: "-v", Bool: true, MultipleFlagsStart: true, Command: []string{"tldr"}, Index: 1},
+					mojo.FlagObject{Name: "-b", Bool: true, Command: []string{"tldr"}, Index: 1},
+					mojo.FlagObject{Name: "-l", Value: "5", MultipleFlagsEnd: true, Command: []string{"tldr"}, Index: 1},
 					mojo.ArgumentObject{Value: "nmap"},
 				},
 			},
@@ -173,8 +179,8 @@ func TestParse(t *testing.T) {
 			want: rets{
 				objs: []mojo.Object{
 					mojo.CommandObject{Name: "tldr"},
-					mojo.FlagObject{Name: "-v", Bool: true, MultipleFlagsStart: true},
-					mojo.FlagObject{Name: "-l", Value: "5", MultipleFlagsEnd: true, CombinedFlagValues: true},
+					mojo.FlagObject{Name: "-v", Bool: true, MultipleFlagsStart: true, Command: []string{"tldr"}, Index: 1},
+					mojo.FlagObject{Name: "-l", Value: "5", MultipleFlagsEnd: true, CombinedFlagValues: true, Command: []string{"tldr"}, Index: 1},
 					mojo.ArgumentObject{Value: "nmap"},
 				},
 			},
@@ -196,7 +202,7 @@ func TestParse(t *testing.T) {
 			want: rets{
 				objs: []mojo.Object{
 					mojo.CommandObject{Name: "tldr"},
-					mojo.CommandObject{Name: "add"},
+					mojo.CommandObject{Name: "add", Canonical: "add"},
 					mojo.ArgumentObject{Value: "nmap"},
 				},
 			},
@@ -250,9 +256,987 @@ func TestParse(t *testing.T) {
 			want: rets{
 				objs: []mojo.Object{
 					mojo.CommandObject{Name: "tldr"},
-					mojo.CommandObject{Name: "add"},
-					mojo.FlagObject{Name: "--level", Value: "5"},
+					mojo.CommandObject{Name: "add", Canonical: "add"},
+					mojo.FlagObject{Name: "--level", Value: "5", Canonical: "--level", Command: []string{"tldr", "add"}, Config: &mojo.FlagConfig{Name: "--level"}, Index: 2},
+					mojo.ArgumentObject{Value: "nmap"},
+				},
+			},
+		},
+		{
+			name: "DoubleDash",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Commands: []mojo.CommandConfig{
+							{Name: "add"},
+						},
+					},
+				},
+				args: []string{"tldr", "-v", "--", "-l", "add", "--"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr", DoubleDash: true},
+					mojo.FlagObject{Name: "-v", Bool: true, Command: []string{"tldr"}, Index: 1},
+					mojo.ArgumentObject{Value: "-l", AfterDoubleDash: true},
+					mojo.ArgumentObject{Value: "add", AfterDoubleDash: true},
+					mojo.ArgumentObject{Value: "--", AfterDoubleDash: true},
+				},
+			},
+		},
+		{
+			name: "FlagAliases",
+			args: args{
+				conf: mojo.Config{
+					DisallowUnconfiguredFlags: true,
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{
+								Name:    "--verbose",
+								Aliases: []string{"-v"},
+								Bool:    true,
+							},
+							{
+								Name:    "--level",
+								Aliases: []string{"-l"},
+							},
+						},
+					},
+				},
+				args: []string{"tldr", "-v", "-l", "5", "nmap"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "-v", Canonical: "--verbose", Aliases: []string{"-v"}, Bool: true, Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--verbose", Aliases: []string{"-v"}, Bool: true}, Index: 1},
+					mojo.FlagObject{Name: "-l", Value: "5", Canonical: "--level", Aliases: []string{"-l"}, Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--level", Aliases: []string{"-l"}}, Index: 2},
+					mojo.ArgumentObject{Value: "nmap"},
+				},
+			},
+		},
+		{
+			name: "CombinedBoolFlagValue",
+			args: args{
+				conf: mojo.Config{
+					DisallowUnconfiguredFlags: true,
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{
+								Name: "--verbose",
+								Bool: true,
+							},
+						},
+					},
+				},
+				args: []string{"tldr", "--verbose=false", "nmap"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--verbose", Value: "false", Canonical: "--verbose", CombinedFlagValues: true, Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--verbose", Bool: true}, Index: 1},
+					mojo.ArgumentObject{Value: "nmap"},
+				},
+			},
+		},
+		{
+			name: "ErrRequiredFlag",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{
+								Name:     "--level",
+								Required: true,
+							},
+						},
+					},
+				},
+				args: []string{"tldr", "nmap"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: required flag: --level"),
+			},
+		},
+		{
+			name: "DefaultFlags",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Commands: []mojo.CommandConfig{
+							{
+								Name: "add",
+								Flags: []mojo.FlagConfig{
+									{
+										Name:    "--level",
+										Default: "1",
+									},
+								},
+							},
+						},
+						Flags: []mojo.FlagConfig{
+							{
+								Name:     "--verbose",
+								Aliases:  []string{"-v"},
+								Bool:     true,
+								Default:  "false",
+								Required: true,
+							},
+							{
+								Name:     "--format",
+								Default:  "text",
+								Required: true,
+							},
+						},
+					},
+				},
+				args: []string{"tldr", "add", "--format", "json", "nmap"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--verbose", Value: "false", Canonical: "--verbose", Aliases: []string{"-v"}, Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--verbose", Aliases: []string{"-v"}, Bool: true, Default: "false", Required: true}, Source: mojo.SourceDefault},
+					mojo.CommandObject{Name: "add", Canonical: "add"},
+					mojo.FlagObject{Name: "--format", Value: "json", Canonical: "--format", Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--format", Default: "text", Required: true}, Index: 2},
 					mojo.ArgumentObject{Value: "nmap"},
+					mojo.FlagObject{Name: "--level", Value: "1", Canonical: "--level", Command: []string{"tldr", "add"}, Config: &mojo.FlagConfig{Name: "--level", Default: "1"}, Source: mojo.SourceDefault},
+				},
+			},
+		},
+		{
+			name: "EnvFlags",
+			args: args{
+				conf: mojo.Config{
+					EnvPrefix: "TLDR",
+					LookupEnv: func(key string) (string, bool) {
+						value, ok := map[string]string{
+							"TLDR_LOG_LEVEL": "5",
+							"TLDR_FORMAT":    "yaml",
+							"PAGER":          "less",
+						}[key]
+						return value, ok
+					},
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{
+								Name:    "--log-level",
+								Default: "1",
+							},
+							{
+								Name: "--format",
+							},
+							{
+								Name: "--pager",
+								Env:  "PAGER",
+							},
+							{
+								Name:    "--color",
+								Default: "auto",
+							},
+						},
+					},
+				},
+				args: []string{"tldr", "--format", "json"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--format", Value: "json", Canonical: "--format", Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--format"}, Index: 1},
+					mojo.FlagObject{Name: "--log-level", Value: "5", Canonical: "--log-level", Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--log-level", Default: "1"}, Source: mojo.SourceEnv},
+					mojo.FlagObject{Name: "--pager", Value: "less", Canonical: "--pager", Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--pager", Env: "PAGER"}, Source: mojo.SourceEnv},
+					mojo.FlagObject{Name: "--color", Value: "auto", Canonical: "--color", Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--color", Default: "auto"}, Source: mojo.SourceDefault},
+				},
+			},
+		},
+		{
+			name: "ErrTooFewArguments",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "cp",
+						Arguments: []mojo.ArgumentConfig{
+							{Name: "SRC", Variadic: true},
+							{Name: "DST"},
+						},
+					},
+				},
+				args: []string{"cp", "a.txt"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: too few arguments: DST"),
+			},
+		},
+		{
+			name: "ErrTooManyArguments",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "git",
+						Commands: []mojo.CommandConfig{
+							{
+								Name: "clone",
+								Arguments: []mojo.ArgumentConfig{
+									{Name: "REPOSITORY"},
+									{Name: "DIRECTORY", Optional: true},
+								},
+							},
+						},
+					},
+				},
+				args: []string{"git", "-C", "src", "clone", "a", "b", "c"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: too many arguments: 2"),
+			},
+		},
+		{
+			name: "Arguments",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "cp",
+						Arguments: []mojo.ArgumentConfig{
+							{Name: "SRC", Variadic: true, Max: 2},
+							{Name: "DST"},
+							{Name: "MODE", Optional: true},
+						},
+					},
+				},
+				args: []string{"cp", "a.txt", "b.txt", "c.txt", "d.txt"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "cp"},
+					mojo.ArgumentObject{Value: "a.txt", Name: "SRC"},
+					mojo.ArgumentObject{Value: "b.txt", Name: "SRC"},
+					mojo.ArgumentObject{Value: "c.txt", Name: "DST"},
+					mojo.ArgumentObject{Value: "d.txt", Name: "MODE"},
+				},
+			},
+		},
+		{
+			name: "ErrConflictingFlags",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{Name: "--json", Bool: true},
+							{Name: "--yaml", Aliases: []string{"-y"}, Bool: true},
+						},
+						Commands: []mojo.CommandConfig{
+							{
+								Name: "list",
+								Constraints: []mojo.FlagConstraint{
+									{Kind: mojo.AtMostOne, Flags: []string{"--json", "-y"}},
+								},
+							},
+						},
+					},
+				},
+				args: []string{"tldr", "--json", "list", "-y"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: conflicting flags: --json, --yaml"),
+			},
+		},
+		{
+			name: "ErrMissingOneOfFlags",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{Name: "--json", Bool: true},
+							{Name: "--format", Default: "text"},
+						},
+						Constraints: []mojo.FlagConstraint{
+							{Kind: mojo.ExactlyOne, Flags: []string{"--json", "--format"}},
+						},
+					},
+				},
+				args: []string{"tldr"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: missing one of flags: --json, --format"),
+			},
+		},
+		{
+			name: "ErrIncompleteFlags",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Constraints: []mojo.FlagConstraint{
+							{Kind: mojo.AllOrNone, Flags: []string{"--user", "--password", "--host"}},
+						},
+					},
+				},
+				args: []string{"tldr", "--user", "admin"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: incomplete flags: --password, --host"),
+			},
+		},
+		{
+			name: "ErrMissingRequiredFlags",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Constraints: []mojo.FlagConstraint{
+							{Kind: mojo.Requires, Flags: []string{"--output", "--format"}},
+						},
+					},
+				},
+				args: []string{"tldr", "--output", "out.txt"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: missing required flags: --format (required by --output)"),
+			},
+		},
+		{
+			name: "Constraints",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Constraints: []mojo.FlagConstraint{
+							{Kind: mojo.ExactlyOne, Flags: []string{"--json", "--yaml"}},
+							{Kind: mojo.Requires, Flags: []string{"--output", "--format"}},
+						},
+					},
+				},
+				args: []string{"tldr", "--yaml"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--yaml", Bool: true, Command: []string{"tldr"}, Index: 1},
+				},
+			},
+		},
+		{
+			name: "ErrInvalidChoice",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{Name: "--format", Choices: []string{"json", "yaml", "text"}},
+						},
+					},
+				},
+				args: []string{"tldr", "--format=yml"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid choice: --format (choices: json, yaml, text) (did you mean yaml?)"),
+			},
+		},
+		{
+			name: "ErrOutOfRange",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{Name: "--level", Aliases: []string{"-l"}, Validate: mojo.ValidateRange(1, 5)},
+						},
+					},
+				},
+				args: []string{"tldr", "-l", "6"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: value out of range: -l"),
+			},
+		},
+		{
+			name: "ErrPatternMismatch",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{Name: "--platform", Default: "win", Validate: mojo.ValidatePattern("^(linux|osx)$")},
+						},
+					},
+				},
+				args: []string{"tldr"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: value doesn't match pattern: --platform"),
+			},
+		},
+		{
+			name: "ValidValues",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name:  "tldr",
+						Flags: validFlags,
+					},
+				},
+				args: []string{"tldr", "--format", "yaml", "--level", "5"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--format", Value: "yaml", Canonical: "--format", Command: []string{"tldr"}, Config: &validFlags[0], Index: 1},
+					mojo.FlagObject{Name: "--level", Value: "5", Canonical: "--level", Command: []string{"tldr"}, Config: &validFlags[1], Index: 3},
+				},
+			},
+		},
+		{
+			name: "ErrInvalidFlagNegated",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{Name: "--color", Bool: true, Negatable: true},
+						},
+					},
+				},
+				args: []string{"tldr", "--no-color=true"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid flag: --no-color"),
+			},
+		},
+		{
+			name: "NegatedFlag",
+			args: args{
+				conf: mojo.Config{
+					DisallowUnconfiguredFlags: true,
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{Name: "--color", Bool: true, Negatable: true},
+						},
+					},
+				},
+				args: []string{"tldr", "--color", "--no-color", "nmap"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--color", Canonical: "--color", Bool: true, Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--color", Bool: true, Negatable: true}, Index: 1},
+					mojo.FlagObject{Name: "--no-color", Canonical: "--color", Bool: true, Negated: true, Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--color", Bool: true, Negatable: true}, Index: 2},
+					mojo.ArgumentObject{Value: "nmap"},
+				},
+			},
+		},
+		{
+			name: "CountingFlags",
+			args: args{
+				conf: mojo.Config{
+					AllowMutipleFlags: true,
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{Name: "--verbose", Aliases: []string{"-v"}, Bool: true, Count: true},
+						},
+					},
+				},
+				args: []string{"tldr", "-vv", "--verbose=3"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "-v", Canonical: "--verbose", Aliases: []string{"-v"}, Bool: true, Counter: true, MultipleFlagsStart: true, Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--verbose", Aliases: []string{"-v"}, Bool: true, Count: true}, Index: 1},
+					mojo.FlagObject{Name: "-v", Canonical: "--verbose", Aliases: []string{"-v"}, Bool: true, Counter: true, MultipleFlagsEnd: true, Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--verbose", Aliases: []string{"-v"}, Bool: true, Count: true}, Index: 1},
+					mojo.FlagObject{Name: "--verbose", Value: "3", Canonical: "--verbose", Aliases: []string{"-v"}, Counter: true, CombinedFlagValues: true, Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--verbose", Aliases: []string{"-v"}, Bool: true, Count: true}, Index: 2},
+				},
+			},
+		},
+		{
+			name: "OptionalValueFlags",
+			args: args{
+				conf: mojo.Config{
+					DisallowUnconfiguredFlags: true,
+					Root: mojo.CommandConfig{
+						Name: "ls",
+						Flags: []mojo.FlagConfig{
+							{Name: "--color", Aliases: []string{"-c"}, OptionalValue: true, Implicit: "always"},
+						},
+					},
+				},
+				args: []string{"ls", "--color", "file.txt", "-cnever", "--color=auto"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "ls"},
+					mojo.FlagObject{Name: "--color", Value: "always", Canonical: "--color", Aliases: []string{"-c"}, OptionalValue: true, ImplicitValue: true, Command: []string{"ls"}, Config: &mojo.FlagConfig{Name: "--color", Aliases: []string{"-c"}, OptionalValue: true, Implicit: "always"}, Index: 1},
+					mojo.ArgumentObject{Value: "file.txt"},
+					mojo.FlagObject{Name: "-c", Value: "never", Canonical: "--color", Aliases: []string{"-c"}, OptionalValue: true, AttachedFlagValue: true, Command: []string{"ls"}, Config: &mojo.FlagConfig{Name: "--color", Aliases: []string{"-c"}, OptionalValue: true, Implicit: "always"}, Index: 3},
+					mojo.FlagObject{Name: "--color", Value: "auto", Canonical: "--color", Aliases: []string{"-c"}, OptionalValue: true, CombinedFlagValues: true, Command: []string{"ls"}, Config: &mojo.FlagConfig{Name: "--color", Aliases: []string{"-c"}, OptionalValue: true, Implicit: "always"}, Index: 4},
+				},
+			},
+		},
+		{
+			name: "FlagValueWithDash",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tail",
+						Flags: []mojo.FlagConfig{
+							{Name: "--offset"},
+						},
+					},
+				},
+				args: []string{"tail", "--offset", "-5", "-f"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tail"},
+					mojo.FlagObject{Name: "--offset", Value: "-5", Canonical: "--offset", Command: []string{"tail"}, Config: &mojo.FlagConfig{Name: "--offset"}, Index: 1},
+					mojo.FlagObject{Name: "-f", Bool: true, Command: []string{"tail"}, Index: 3},
+				},
+			},
+		},
+		{
+			name: "AllowNegativeNumbers",
+			args: args{
+				conf: mojo.Config{
+					AllowNegativeNumbers: true,
+					Root: mojo.CommandConfig{
+						Name: "calc",
+						Flags: []mojo.FlagConfig{
+							{Name: "-1", Bool: true},
+						},
+					},
+				},
+				args: []string{"calc", "--scale", "-0.5", "-1", "-3", "-x"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "calc"},
+					mojo.FlagObject{Name: "--scale", Value: "-0.5", Command: []string{"calc"}, Index: 1},
+					mojo.FlagObject{Name: "-1", Canonical: "-1", Bool: true, Command: []string{"calc"}, Config: &mojo.FlagConfig{Name: "-1", Bool: true}, Index: 3},
+					mojo.ArgumentObject{Value: "-3"},
+					mojo.FlagObject{Name: "-x", Bool: true, Command: []string{"calc"}, Index: 5},
+				},
+			},
+		},
+		{
+			name: "AttachedFlagValues",
+			args: args{
+				conf: mojo.Config{
+					AllowMutipleFlags: true,
+					Root: mojo.CommandConfig{
+						Name: "make",
+						Flags: []mojo.FlagConfig{
+							{Name: "-j"},
+							{Name: "-O"},
+							{Name: "-k", Bool: true},
+							{Name: "-s", Bool: true},
+						},
+					},
+				},
+				args: []string{"make", "-ksj8", "-O2", "-j=4"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "make"},
+					mojo.FlagObject{Name: "-k", Canonical: "-k", Bool: true, MultipleFlagsStart: true, Command: []string{"make"}, Config: &mojo.FlagConfig{Name: "-k", Bool: true}, Index: 1},
+					mojo.FlagObject{Name: "-s", Canonical: "-s", Bool: true, Command: []string{"make"}, Config: &mojo.FlagConfig{Name: "-s", Bool: true}, Index: 1},
+					mojo.FlagObject{Name: "-j", Value: "8", Canonical: "-j", MultipleFlagsEnd: true, AttachedFlagValue: true, Command: []string{"make"}, Config: &mojo.FlagConfig{Name: "-j"}, Index: 1},
+					mojo.FlagObject{Name: "-O", Value: "2", Canonical: "-O", AttachedFlagValue: true, Command: []string{"make"}, Config: &mojo.FlagConfig{Name: "-O"}, Index: 2},
+					mojo.FlagObject{Name: "-j", Value: "4", Canonical: "-j", CombinedFlagValues: true, Command: []string{"make"}, Config: &mojo.FlagConfig{Name: "-j"}, Index: 3},
+				},
+			},
+		},
+		{
+			name: "FlagAbbreviations",
+			args: args{
+				conf: mojo.Config{
+					AllowFlagAbbreviations: true,
+					Root: mojo.CommandConfig{
+						Name: "tool",
+						Commands: []mojo.CommandConfig{
+							{
+								Name: "build",
+								Flags: []mojo.FlagConfig{
+									{Name: "--output"},
+								},
+							},
+						},
+						Flags: []mojo.FlagConfig{
+							{Name: "--verbose", Bool: true, Negatable: true},
+							{Name: "--version", Bool: true},
+							{Name: "--vers", Bool: true},
+						},
+					},
+				},
+				args: []string{"tool", "--verb", "--no-v", "--vers", "build", "--out=bin", "--o", "dist"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tool"},
+					mojo.FlagObject{Name: "--verbose", Abbreviation: "--verb", Canonical: "--verbose", Bool: true, Command: []string{"tool"}, Config: &mojo.FlagConfig{Name: "--verbose", Bool: true, Negatable: true}, Index: 1},
+					mojo.FlagObject{Name: "--no-verbose", Abbreviation: "--no-v", Canonical: "--verbose", Bool: true, Negated: true, Command: []string{"tool"}, Config: &mojo.FlagConfig{Name: "--verbose", Bool: true, Negatable: true}, Index: 2},
+					mojo.FlagObject{Name: "--vers", Canonical: "--vers", Bool: true, Command: []string{"tool"}, Config: &mojo.FlagConfig{Name: "--vers", Bool: true}, Index: 3},
+					mojo.CommandObject{Name: "build", Canonical: "build"},
+					mojo.FlagObject{Name: "--output", Value: "bin", Abbreviation: "--out", Canonical: "--output", CombinedFlagValues: true, Command: []string{"tool", "build"}, Config: &mojo.FlagConfig{Name: "--output"}, Index: 5},
+					mojo.FlagObject{Name: "--output", Value: "dist", Abbreviation: "--o", Canonical: "--output", Command: []string{"tool", "build"}, Config: &mojo.FlagConfig{Name: "--output"}, Index: 6},
+				},
+			},
+		},
+		{
+			name: "ErrAmbiguousFlag",
+			args: args{
+				conf: mojo.Config{
+					AllowFlagAbbreviations: true,
+					Root: mojo.CommandConfig{
+						Name: "tool",
+						Flags: []mojo.FlagConfig{
+							{Name: "--verbose", Aliases: []string{"--verb"}, Bool: true},
+							{Name: "--version", Bool: true},
+						},
+					},
+				},
+				args: []string{"tool", "--ver"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: ambiguous flag: --ver (did you mean --verbose or --version?)"),
+			},
+		},
+		{
+			name: "CommandAliases",
+			args: args{
+				conf: mojo.Config{
+					AllowCommandAbbreviations: true,
+					Root: mojo.CommandConfig{
+						Name: "pkg",
+						Commands: []mojo.CommandConfig{
+							{
+								Name:    "remove",
+								Aliases: []string{"rm"},
+								Commands: []mojo.CommandConfig{
+									{
+										Name: "cache",
+										Flags: []mojo.FlagConfig{
+											{Name: "--all", Bool: true},
+										},
+									},
+								},
+							},
+							{Name: "install"},
+							{Name: "info"},
+						},
+					},
+				},
+				args: []string{"pkg", "rm", "ca", "--all", "in"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "pkg"},
+					mojo.CommandObject{Name: "rm", Canonical: "remove"},
+					mojo.CommandObject{Name: "ca", Canonical: "cache"},
+					mojo.FlagObject{Name: "--all", Canonical: "--all", Bool: true, Command: []string{"pkg", "remove", "cache"}, Config: &mojo.FlagConfig{Name: "--all", Bool: true}, Index: 3},
+					mojo.ArgumentObject{Value: "in"},
+				},
+			},
+		},
+		{
+			name: "ErrUnconfiguredFlagSuggestions",
+			args: args{
+				conf: mojo.Config{
+					DisallowUnconfiguredFlags: true,
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Flags: []mojo.FlagConfig{
+							{Name: "--verbose", Bool: true, Negatable: true},
+							{Name: "--version", Bool: true},
+							{Name: "--level"},
+						},
+					},
+				},
+				args: []string{"tldr", "--no-verbsoe"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: unconfigured flag: --no-verbsoe (did you mean --no-verbose or --verbose?)"),
+			},
+		},
+		{
+			name: "ErrCommandNotFound",
+			args: args{
+				conf: mojo.Config{
+					DisallowUnconfiguredCommands: true,
+					Root: mojo.CommandConfig{
+						Name: "pkg",
+						Commands: []mojo.CommandConfig{
+							{Name: "install"},
+							{Name: "remove", Aliases: []string{"rm"}},
+						},
+					},
+				},
+				args: []string{"pkg", "instal", "vim", "-v"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: command not found: instal (did you mean install?)"),
+			},
+		},
+		{
+			name: "UnconfiguredCommand",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "pkg",
+						Commands: []mojo.CommandConfig{
+							{Name: "install"},
+						},
+					},
+				},
+				args: []string{"pkg", "instal", "vim"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "pkg"},
+					mojo.ArgumentObject{Value: "instal"},
+					mojo.ArgumentObject{Value: "vim"},
+				},
+			},
+		},
+		{
+			name: "LocalFlags",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Commands: []mojo.CommandConfig{
+							{Name: "add"},
+						},
+						Flags: []mojo.FlagConfig{
+							{Name: "--verbose", Bool: true},
+							{Name: "--version", Bool: true, Local: true},
+							{Name: "--level", Default: "1", Local: true},
+						},
+					},
+				},
+				args: []string{"tldr", "add", "--verbose", "--version"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--level", Value: "1", Canonical: "--level", Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--level", Default: "1", Local: true}, Source: mojo.SourceDefault},
+					mojo.CommandObject{Name: "add", Canonical: "add"},
+					mojo.FlagObject{Name: "--verbose", Canonical: "--verbose", Bool: true, Command: []string{"tldr"}, Config: &mojo.FlagConfig{Name: "--verbose", Bool: true}, Index: 2},
+					mojo.FlagObject{Name: "--version", Bool: true, Command: []string{"tldr", "add"}, Index: 3},
+				},
+			},
+		},
+		{
+			name: "ErrLocalFlagInSubcommand",
+			args: args{
+				conf: mojo.Config{
+					DisallowUnconfiguredFlags: true,
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Commands: []mojo.CommandConfig{
+							{Name: "add"},
+						},
+						Flags: []mojo.FlagConfig{
+							{Name: "--version", Bool: true, Local: true},
+						},
+					},
+				},
+				args: []string{"tldr", "add", "--version"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: unconfigured flag: --version"),
+			},
+		},
+		{
+			name: "FlagCommands",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tool",
+						Commands: []mojo.CommandConfig{
+							{
+								Name: "sub",
+								Flags: []mojo.FlagConfig{
+									{Name: "--level", Description: "Sub level."},
+								},
+							},
+						},
+						Flags: []mojo.FlagConfig{
+							{Name: "--level", Description: "Tool level."},
+							{Name: "--verbose", Bool: true},
+						},
+					},
+				},
+				args: []string{"tool", "--level", "1", "sub", "--level", "2", "--verbose", "-x"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tool"},
+					mojo.FlagObject{Name: "--level", Value: "1", Canonical: "--level", Command: []string{"tool"}, Config: &mojo.FlagConfig{Name: "--level", Description: "Tool level."}, Index: 1},
+					mojo.CommandObject{Name: "sub", Canonical: "sub"},
+					mojo.FlagObject{Name: "--level", Value: "2", Canonical: "--level", Command: []string{"tool", "sub"}, Config: &mojo.FlagConfig{Name: "--level", Description: "Sub level."}, Index: 4},
+					mojo.FlagObject{Name: "--verbose", Canonical: "--verbose", Bool: true, Command: []string{"tool"}, Config: &mojo.FlagConfig{Name: "--verbose", Bool: true}, Index: 6},
+					mojo.FlagObject{Name: "-x", Bool: true, Command: []string{"tool", "sub"}, Index: 7},
+				},
+			},
+		},
+		{
+			name: "ErrCommandNotFoundWithoutSuggestions",
+			args: args{
+				conf: mojo.Config{
+					DisallowUnconfiguredCommands: true,
+					Root: mojo.CommandConfig{
+						Name: "pkg",
+						Commands: []mojo.CommandConfig{
+							{Name: "install"},
+						},
+					},
+				},
+				args: []string{"pkg", "vim"},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: command not found: vim"),
+			},
+		},
+		{
+			name: "SameFlagInCommandAndSubcommand",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tool",
+						Commands: []mojo.CommandConfig{
+							{
+								Name: "sub",
+								Flags: []mojo.FlagConfig{
+									{Name: "--level"},
+								},
+							},
+						},
+						Flags: []mojo.FlagConfig{
+							{Name: "--level", Default: "1"},
+						},
+					},
+				},
+				args: []string{"tool", "sub", "--level", "3"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tool"},
+					mojo.CommandObject{Name: "sub", Canonical: "sub"},
+					mojo.FlagObject{Name: "--level", Value: "3", Canonical: "--level", Command: []string{"tool", "sub"}, Config: &mojo.FlagConfig{Name: "--level"}, Index: 2},
+				},
+			},
+		},
+		{
+			name: "OptionalValueWithChoices",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "ls",
+						Flags: []mojo.FlagConfig{
+							{Name: "--color", OptionalValue: true, Choices: []string{"always", "never"}},
+						},
+					},
+				},
+				args: []string{"ls", "--color"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "ls"},
+					mojo.FlagObject{Name: "--color", Canonical: "--color", OptionalValue: true, ImplicitValue: true, Command: []string{"ls"}, Config: &mojo.FlagConfig{Name: "--color", OptionalValue: true, Choices: []string{"always", "never"}}, Index: 1},
+				},
+			},
+		},
+		{
+			name: "SameFlagInCommandAndSubcommandNotPassed",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tool",
+						Commands: []mojo.CommandConfig{
+							{
+								Name: "sub",
+								Flags: []mojo.FlagConfig{
+									{Name: "--level"},
+								},
+							},
+						},
+						Flags: []mojo.FlagConfig{
+							{Name: "--level", Default: "1"},
+						},
+					},
+				},
+				args: []string{"tool", "sub"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tool"},
+					mojo.FlagObject{Name: "--level", Value: "1", Canonical: "--level", Command: []string{"tool"}, Config: &mojo.FlagConfig{Name: "--level", Default: "1"}, Source: mojo.SourceDefault},
+					mojo.CommandObject{Name: "sub", Canonical: "sub"},
+				},
+			},
+		},
+		{
+			name: "RequiredFlagInCommandAndSubcommand",
+			args: args{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tool",
+						Commands: []mojo.CommandConfig{
+							{
+								Name: "sub",
+								Flags: []mojo.FlagConfig{
+									{Name: "--level"},
+								},
+							},
+						},
+						Flags: []mojo.FlagConfig{
+							{Name: "--level", Required: true},
+						},
+					},
+				},
+				args: []string{"tool", "sub", "--level", "3"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tool"},
+					mojo.CommandObject{Name: "sub", Canonical: "sub"},
+					mojo.FlagObject{Name: "--level", Value: "3", Canonical: "--level", Command: []string{"tool", "sub"}, Config: &mojo.FlagConfig{Name: "--level"}, Index: 2},
+				},
+			},
+		},
+		{
+			name: "ConstraintsWithEnvFlags",
+			args: args{
+				conf: mojo.Config{
+					LookupEnv: func(key string) (string, bool) {
+						if key == "JSON" {
+							return "true", true
+						}
+						return "", false
+					},
+					Root: mojo.CommandConfig{
+						Name: "tool",
+						Flags: []mojo.FlagConfig{
+							{Name: "--json", Bool: true, Env: "JSON"},
+						},
+						Constraints: []mojo.FlagConstraint{
+							{Kind: mojo.AtMostOne, Flags: []string{"--json", "--yaml"}},
+						},
+					},
+				},
+				args: []string{"tool", "--yaml"},
+			},
+			want: rets{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tool"},
+					mojo.FlagObject{Name: "--yaml", Bool: true, Command: []string{"tool"}, Index: 1},
+					mojo.FlagObject{Name: "--json", Value: "true", Canonical: "--json", Command: []string{"tool"}, Config: &mojo.FlagConfig{Name: "--json", Bool: true, Env: "JSON"}, Source: mojo.SourceEnv},
 				},
 			},
 		},
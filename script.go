@@ -0,0 +1,554 @@
+package mojo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// completeCommand is the name of the hidden command that completion scripts
+// call to complete values that cannot be determined from the configuration
+// (e.g. flag values and arguments).
+//
+// The command is called with the arguments up to and including the argument
+// being completed, excluding the name of the root command. Each line of its
+// output contains a candidate, optionally followed by a tab and its
+// description.
+const completeCommand = "__complete"
+
+// BashCompletion returns the bash completion script for the given
+// configuration.
+//
+// Command and flag names are completed from the configuration, while flag
+// values and arguments are completed by calling the hidden __complete command
+// of the root command, which should be handled using HandleComplete.
+func BashCompletion(conf Config) string {
+	var (
+		b    strings.Builder
+		name = conf.Root.Name
+		fn   = scriptIdentifier(name)
+		cmds = scriptCommands(conf)
+	)
+
+	fmt.Fprintf(&b, "# bash completion for %s\n", name)
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "_%s_dynamic() {\n", fn)
+	fmt.Fprintf(&b, "    local IFS=$'\\n' line prefix=\"${cur%%\"${COMP_WORDS[COMP_CWORD]}\"}\"\n")
+	fmt.Fprintf(&b, "    COMPREPLY=()\n")
+	fmt.Fprintf(&b, "    for line in $(\"${words[0]}\" %s \"${words[@]:1:cword}\" 2>/dev/null); do\n", completeCommand)
+	fmt.Fprintf(&b, "        line=\"${line%%%%$'\\t'*}\"\n")
+	fmt.Fprintf(&b, "        COMPREPLY+=(\"${line#\"$prefix\"}\")\n")
+	fmt.Fprintf(&b, "    done\n")
+	fmt.Fprintf(&b, "}\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "_%s() {\n", fn)
+	fmt.Fprintf(&b, "    local words=() cword=0 cmdpath=%s skip=0 cur word i\n", shellQuote(name))
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    # Join the words that were split at equals signs.\n")
+	fmt.Fprintf(&b, "    for ((i = 0; i < ${#COMP_WORDS[@]}; i++)); do\n")
+	fmt.Fprintf(&b, "        if ((i > 0)) && [[ ${COMP_WORDS[i]} == = || ${COMP_WORDS[i-1]} == = ]]; then\n")
+	fmt.Fprintf(&b, "            words[${#words[@]}-1]+=\"${COMP_WORDS[i]}\"\n")
+	fmt.Fprintf(&b, "        else\n")
+	fmt.Fprintf(&b, "            words+=(\"${COMP_WORDS[i]}\")\n")
+	fmt.Fprintf(&b, "        fi\n")
+	fmt.Fprintf(&b, "        ((i == COMP_CWORD)) && cword=$((${#words[@]} - 1))\n")
+	fmt.Fprintf(&b, "    done\n")
+	fmt.Fprintf(&b, "    cur=\"${words[cword]}\"\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    for ((i = 1; i < cword; i++)); do\n")
+	fmt.Fprintf(&b, "        word=\"${words[i]}\"\n")
+	fmt.Fprintf(&b, "        if ((skip)); then\n")
+	fmt.Fprintf(&b, "            skip=0\n")
+	fmt.Fprintf(&b, "            continue\n")
+	fmt.Fprintf(&b, "        fi\n")
+	fmt.Fprintf(&b, "        if [[ $word == -- ]]; then\n")
+	fmt.Fprintf(&b, "            _%s_dynamic\n", fn)
+	fmt.Fprintf(&b, "            return\n")
+	fmt.Fprintf(&b, "        fi\n")
+	fmt.Fprintf(&b, "        case \"$cmdpath\" in\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "        %s)\n", shellQuote(cmd.path))
+		fmt.Fprintf(&b, "            case \"$word\" in\n")
+		for _, subcmd := range cmd.cmd.Commands {
+			fmt.Fprintf(&b, "            %s) cmdpath=%s ;;\n", strings.Join(shellQuoteAll(subcmd.names()), "|"), shellQuote(cmd.path+" "+subcmd.Name))
+		}
+		if names := cmd.valueFlagNames(); len(names) > 0 {
+			fmt.Fprintf(&b, "            %s) skip=1 ;;\n", strings.Join(shellQuoteAll(names), "|"))
+		}
+		fmt.Fprintf(&b, "            esac\n")
+		fmt.Fprintf(&b, "            ;;\n")
+	}
+	fmt.Fprintf(&b, "        esac\n")
+	fmt.Fprintf(&b, "    done\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    if ((skip)) || [[ $cur == --*=* ]]; then\n")
+	fmt.Fprintf(&b, "        _%s_dynamic\n", fn)
+	fmt.Fprintf(&b, "        return\n")
+	fmt.Fprintf(&b, "    fi\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    local candidates\n")
+	fmt.Fprintf(&b, "    case \"$cmdpath\" in\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "    %s)\n", shellQuote(cmd.path))
+		fmt.Fprintf(&b, "        if [[ $cur == -* ]]; then\n")
+		fmt.Fprintf(&b, "            candidates=%s\n", shellQuote(strings.Join(cmd.flagNames(), " ")))
+		fmt.Fprintf(&b, "        else\n")
+		if cmd.completesArguments() {
+			fmt.Fprintf(&b, "            _%s_dynamic\n", fn)
+			fmt.Fprintf(&b, "            return\n")
+		} else {
+			fmt.Fprintf(&b, "            candidates=%s\n", shellQuote(strings.Join(cmd.commandNames(), " ")))
+		}
+		fmt.Fprintf(&b, "        fi\n")
+		fmt.Fprintf(&b, "        ;;\n")
+	}
+	fmt.Fprintf(&b, "    esac\n")
+	fmt.Fprintf(&b, "    COMPREPLY=($(compgen -W \"$candidates\" -- \"$cur\"))\n")
+	fmt.Fprintf(&b, "}\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "complete -F _%s %s\n", fn, shellQuote(name))
+
+	return b.String()
+}
+
+// ZshCompletion returns the zsh completion script for the given configuration.
+//
+// Check BashCompletion for more information.
+func ZshCompletion(conf Config) string {
+	var (
+		b    strings.Builder
+		name = conf.Root.Name
+		fn   = scriptIdentifier(name)
+		cmds = scriptCommands(conf)
+	)
+
+	fmt.Fprintf(&b, "#compdef %s\n", name)
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "# zsh completion for %s\n", name)
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "_%s_dynamic() {\n", fn)
+	fmt.Fprintf(&b, "    local -a candidates\n")
+	fmt.Fprintf(&b, "    local line\n")
+	fmt.Fprintf(&b, "    for line in \"${(@f)$(\"${words[1]}\" %s \"${(@)words[2,CURRENT]}\" 2>/dev/null)}\"; do\n", completeCommand)
+	fmt.Fprintf(&b, "        [[ -z $line ]] && continue\n")
+	fmt.Fprintf(&b, "        if [[ $line == *$'\\t'* ]]; then\n")
+	fmt.Fprintf(&b, "            candidates+=(\"${${line%%%%$'\\t'*}//:/\\\\:}:${line#*$'\\t'}\")\n")
+	fmt.Fprintf(&b, "        else\n")
+	fmt.Fprintf(&b, "            candidates+=(\"${line//:/\\\\:}\")\n")
+	fmt.Fprintf(&b, "        fi\n")
+	fmt.Fprintf(&b, "    done\n")
+	fmt.Fprintf(&b, "    _describe 'value' candidates\n")
+	fmt.Fprintf(&b, "}\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "_%s() {\n", fn)
+	fmt.Fprintf(&b, "    local cmdpath=%s skip=0 cur=\"${words[CURRENT]}\" word i\n", shellQuote(name))
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    for ((i = 2; i < CURRENT; i++)); do\n")
+	fmt.Fprintf(&b, "        word=\"${words[i]}\"\n")
+	fmt.Fprintf(&b, "        if ((skip)); then\n")
+	fmt.Fprintf(&b, "            skip=0\n")
+	fmt.Fprintf(&b, "            continue\n")
+	fmt.Fprintf(&b, "        fi\n")
+	fmt.Fprintf(&b, "        if [[ $word == -- ]]; then\n")
+	fmt.Fprintf(&b, "            _%s_dynamic\n", fn)
+	fmt.Fprintf(&b, "            return\n")
+	fmt.Fprintf(&b, "        fi\n")
+	fmt.Fprintf(&b, "        case \"$cmdpath\" in\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "        %s)\n", shellQuote(cmd.path))
+		fmt.Fprintf(&b, "            case \"$word\" in\n")
+		for _, subcmd := range cmd.cmd.Commands {
+			fmt.Fprintf(&b, "            %s) cmdpath=%s ;;\n", strings.Join(shellQuoteAll(subcmd.names()), "|"), shellQuote(cmd.path+" "+subcmd.Name))
+		}
+		if names := cmd.valueFlagNames(); len(names) > 0 {
+			fmt.Fprintf(&b, "            %s) skip=1 ;;\n", strings.Join(shellQuoteAll(names), "|"))
+		}
+		fmt.Fprintf(&b, "            esac\n")
+		fmt.Fprintf(&b, "            ;;\n")
+	}
+	fmt.Fprintf(&b, "        esac\n")
+	fmt.Fprintf(&b, "    done\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    if ((skip)) || [[ $cur == --*=* ]]; then\n")
+	fmt.Fprintf(&b, "        _%s_dynamic\n", fn)
+	fmt.Fprintf(&b, "        return\n")
+	fmt.Fprintf(&b, "    fi\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    local -a candidates\n")
+	fmt.Fprintf(&b, "    case \"$cmdpath\" in\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "    %s)\n", shellQuote(cmd.path))
+		fmt.Fprintf(&b, "        if [[ $cur == -* ]]; then\n")
+		fmt.Fprintf(&b, "            candidates=(%s)\n", strings.Join(shellQuoteAll(zshCandidates(cmd.flagCandidates())), " "))
+		fmt.Fprintf(&b, "        else\n")
+		if cmd.completesArguments() {
+			fmt.Fprintf(&b, "            _%s_dynamic\n", fn)
+			fmt.Fprintf(&b, "            return\n")
+		} else {
+			fmt.Fprintf(&b, "            candidates=(%s)\n", strings.Join(shellQuoteAll(zshCandidates(cmd.commandCandidates())), " "))
+		}
+		fmt.Fprintf(&b, "        fi\n")
+		fmt.Fprintf(&b, "        ;;\n")
+	}
+	fmt.Fprintf(&b, "    esac\n")
+	fmt.Fprintf(&b, "    _describe 'completion' candidates\n")
+	fmt.Fprintf(&b, "}\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "compdef _%s %s\n", fn, shellQuote(name))
+
+	return b.String()
+}
+
+// FishCompletion returns the fish completion script for the given
+// configuration.
+//
+// Check BashCompletion for more information.
+func FishCompletion(conf Config) string {
+	var (
+		b    strings.Builder
+		name = conf.Root.Name
+		fn   = scriptIdentifier(name)
+		cmds = scriptCommands(conf)
+	)
+
+	fmt.Fprintf(&b, "# fish completion for %s\n", name)
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "function __%s_complete\n", fn)
+	fmt.Fprintf(&b, "    set -l tokens (commandline -opc)\n")
+	fmt.Fprintf(&b, "    set -l cur (commandline -ct)\n")
+	fmt.Fprintf(&b, "    set -l cmdpath %s\n", fishQuote(name))
+	fmt.Fprintf(&b, "    set -l skip 0\n")
+	fmt.Fprintf(&b, "    set -l dynamic 0\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    for word in $tokens[2..-1]\n")
+	fmt.Fprintf(&b, "        if test $skip = 1\n")
+	fmt.Fprintf(&b, "            set skip 0\n")
+	fmt.Fprintf(&b, "            continue\n")
+	fmt.Fprintf(&b, "        end\n")
+	fmt.Fprintf(&b, "        if test \"$word\" = --\n")
+	fmt.Fprintf(&b, "            set dynamic 1\n")
+	fmt.Fprintf(&b, "            break\n")
+	fmt.Fprintf(&b, "        end\n")
+	fmt.Fprintf(&b, "        switch $cmdpath\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "            case %s\n", fishQuote(cmd.path))
+
+		// Use if statements since case treats words starting with
+		// dashes as options.
+		keyword := "if"
+		for _, subcmd := range cmd.cmd.Commands {
+			fmt.Fprintf(&b, "                %s contains -- \"$word\" %s\n", keyword, strings.Join(fishQuoteAll(subcmd.names()), " "))
+			fmt.Fprintf(&b, "                    set cmdpath %s\n", fishQuote(cmd.path+" "+subcmd.Name))
+			keyword = "else if"
+		}
+		if names := cmd.valueFlagNames(); len(names) > 0 {
+			fmt.Fprintf(&b, "                %s contains -- \"$word\" %s\n", keyword, strings.Join(fishQuoteAll(names), " "))
+			fmt.Fprintf(&b, "                    set skip 1\n")
+			keyword = "else if"
+		}
+		if keyword != "if" {
+			fmt.Fprintf(&b, "                end\n")
+		}
+	}
+	fmt.Fprintf(&b, "        end\n")
+	fmt.Fprintf(&b, "    end\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    if test $skip = 1 -o $dynamic = 1; or string match -q -- '--*=*' \"$cur\"\n")
+	fmt.Fprintf(&b, "        $tokens[1] %s $tokens[2..-1] \"$cur\" 2>/dev/null\n", completeCommand)
+	fmt.Fprintf(&b, "        return\n")
+	fmt.Fprintf(&b, "    end\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    switch $cmdpath\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "        case %s\n", fishQuote(cmd.path))
+		fmt.Fprintf(&b, "            if string match -q -- '-*' \"$cur\"\n")
+		for _, c := range cmd.flagCandidates() {
+			fmt.Fprintf(&b, "                printf '%%s\\t%%s\\n' %s %s\n", fishQuote(c.name), fishQuote(c.description))
+		}
+		fmt.Fprintf(&b, "            else\n")
+		if cmd.completesArguments() {
+			fmt.Fprintf(&b, "                $tokens[1] %s $tokens[2..-1] \"$cur\" 2>/dev/null\n", completeCommand)
+		} else {
+			for _, c := range cmd.commandCandidates() {
+				fmt.Fprintf(&b, "                printf '%%s\\t%%s\\n' %s %s\n", fishQuote(c.name), fishQuote(c.description))
+			}
+		}
+		fmt.Fprintf(&b, "            end\n")
+	}
+	fmt.Fprintf(&b, "    end\n")
+	fmt.Fprintf(&b, "end\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "complete -c %s -f -a '(__%s_complete)'\n", fishQuote(name), fn)
+
+	return b.String()
+}
+
+// PowerShellCompletion returns the PowerShell completion script for the given
+// configuration.
+//
+// Check BashCompletion for more information.
+func PowerShellCompletion(conf Config) string {
+	var (
+		b    strings.Builder
+		name = conf.Root.Name
+		cmds = scriptCommands(conf)
+	)
+
+	fmt.Fprintf(&b, "# powershell completion for %s\n", name)
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "Register-ArgumentCompleter -Native -CommandName %s -ScriptBlock {\n", powerShellQuote(name))
+	fmt.Fprintf(&b, "    param($wordToComplete, $commandAst, $cursorPosition)\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    $words = @($commandAst.CommandElements | Where-Object { $_.Extent.EndOffset -lt $cursorPosition } | ForEach-Object { $_.ToString() })\n")
+	fmt.Fprintf(&b, "    $cmdpath = %s\n", powerShellQuote(name))
+	fmt.Fprintf(&b, "    $skip = $false\n")
+	fmt.Fprintf(&b, "    $dynamic = $false\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    foreach ($word in ($words | Select-Object -Skip 1)) {\n")
+	fmt.Fprintf(&b, "        if ($skip) {\n")
+	fmt.Fprintf(&b, "            $skip = $false\n")
+	fmt.Fprintf(&b, "            continue\n")
+	fmt.Fprintf(&b, "        }\n")
+	fmt.Fprintf(&b, "        if ($word -ceq '--') {\n")
+	fmt.Fprintf(&b, "            $dynamic = $true\n")
+	fmt.Fprintf(&b, "            break\n")
+	fmt.Fprintf(&b, "        }\n")
+	fmt.Fprintf(&b, "        switch -CaseSensitive ($cmdpath) {\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "            %s {\n", powerShellQuote(cmd.path))
+		fmt.Fprintf(&b, "                switch -CaseSensitive ($word) {\n")
+		for _, subcmd := range cmd.cmd.Commands {
+			for _, name := range subcmd.names() {
+				fmt.Fprintf(&b, "                    %s { $cmdpath = %s }\n", powerShellQuote(name), powerShellQuote(cmd.path+" "+subcmd.Name))
+			}
+		}
+		for _, name := range cmd.valueFlagNames() {
+			fmt.Fprintf(&b, "                    %s { $skip = $true }\n", powerShellQuote(name))
+		}
+		fmt.Fprintf(&b, "                }\n")
+		fmt.Fprintf(&b, "            }\n")
+	}
+	fmt.Fprintf(&b, "        }\n")
+	fmt.Fprintf(&b, "    }\n")
+	fmt.Fprintf(&b, "\n")
+	var paths []string
+	for _, cmd := range cmds {
+		if cmd.completesArguments() {
+			paths = append(paths, powerShellQuote(cmd.path))
+		}
+	}
+	if len(paths) > 0 {
+		fmt.Fprintf(&b, "    if (-not $wordToComplete.StartsWith('-') -and $cmdpath -cin @(%s)) {\n", strings.Join(paths, ", "))
+		fmt.Fprintf(&b, "        $dynamic = $true\n")
+		fmt.Fprintf(&b, "    }\n")
+		fmt.Fprintf(&b, "\n")
+	}
+	fmt.Fprintf(&b, "    if ($skip -or $dynamic -or $wordToComplete -like '--*=*') {\n")
+	fmt.Fprintf(&b, "        & $words[0] %s @($words | Select-Object -Skip 1) $wordToComplete 2>$null | ForEach-Object {\n", completeCommand)
+	fmt.Fprintf(&b, "            $value, $description = $_ -split \"`t\", 2\n")
+	fmt.Fprintf(&b, "            if (-not $description) {\n")
+	fmt.Fprintf(&b, "                $description = $value\n")
+	fmt.Fprintf(&b, "            }\n")
+	fmt.Fprintf(&b, "            [System.Management.Automation.CompletionResult]::new($value, $value, 'ParameterValue', $description)\n")
+	fmt.Fprintf(&b, "        }\n")
+	fmt.Fprintf(&b, "        return\n")
+	fmt.Fprintf(&b, "    }\n")
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "    $candidates = switch -CaseSensitive ($cmdpath) {\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "        %s {\n", powerShellQuote(cmd.path))
+		fmt.Fprintf(&b, "            if ($wordToComplete.StartsWith('-')) {\n")
+		for _, c := range cmd.flagCandidates() {
+			fmt.Fprintf(&b, "                [System.Management.Automation.CompletionResult]::new(%s, %s, 'ParameterName', %s)\n", powerShellQuote(c.name), powerShellQuote(c.name), powerShellQuote(c.tooltip()))
+		}
+		fmt.Fprintf(&b, "            } else {\n")
+		for _, c := range cmd.commandCandidates() {
+			fmt.Fprintf(&b, "                [System.Management.Automation.CompletionResult]::new(%s, %s, 'Command', %s)\n", powerShellQuote(c.name), powerShellQuote(c.name), powerShellQuote(c.tooltip()))
+		}
+		fmt.Fprintf(&b, "            }\n")
+		fmt.Fprintf(&b, "        }\n")
+	}
+	fmt.Fprintf(&b, "    }\n")
+	fmt.Fprintf(&b, "    $candidates | Where-Object { $_.CompletionText.StartsWith($wordToComplete, [System.StringComparison]::Ordinal) }\n")
+	fmt.Fprintf(&b, "}\n")
+
+	return b.String()
+}
+
+// scriptCommand represents a command in completion scripts.
+type scriptCommand struct {
+	// path contains the names of the command stack separated by spaces.
+	path string
+	cmd  CommandConfig
+
+	// flags contains the flags that can be used in the command.
+	flags []FlagConfig
+}
+
+// scriptCandidate represents a static candidate in completion scripts.
+type scriptCandidate struct {
+	name        string
+	description string
+}
+
+// scriptCommands returns all the commands in the given configuration, with the
+// root command first.
+func scriptCommands(conf Config) []scriptCommand {
+	var (
+		cmds []scriptCommand
+		walk func(commands []string)
+	)
+	walk = func(commands []string) {
+		cmd := configCommands(conf, commands)[0]
+		cmds = append(cmds, scriptCommand{
+			path:  strings.Join(commands, " "),
+			cmd:   cmd,
+			flags: visibleFlags(conf, commands),
+		})
+		for _, subcmd := range cmd.Commands {
+			walk(append(append([]string(nil), commands...), subcmd.Name))
+		}
+	}
+	walk([]string{conf.Root.Name})
+	return cmds
+}
+
+// commandNames returns the names of the subcommands of the command.
+func (c scriptCommand) commandNames() []string {
+	var names []string
+	for _, candidate := range c.commandCandidates() {
+		names = append(names, candidate.name)
+	}
+	return names
+}
+
+// flagNames returns the names and aliases of the flags of the command.
+func (c scriptCommand) flagNames() []string {
+	var names []string
+	for _, candidate := range c.flagCandidates() {
+		names = append(names, candidate.name)
+	}
+	return names
+}
+
+// valueFlagNames returns the names and aliases of the flags of the command
+// which take values from the next argument.
+func (c scriptCommand) valueFlagNames() []string {
+	var names []string
+	for _, flag := range c.flags {
+		if !flag.Bool && !flag.OptionalValue {
+			names = append(names, flag.Name)
+			names = append(names, flag.Aliases...)
+		}
+	}
+	return names
+}
+
+// completesArguments returns whether the arguments of the command are
+// completed by calling the hidden __complete command.
+func (c scriptCommand) completesArguments() bool {
+	if c.cmd.Complete != nil {
+		return true
+	}
+	for _, arg := range c.cmd.Arguments {
+		if arg.Complete != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// commandCandidates returns the candidates for the subcommands of the command.
+func (c scriptCommand) commandCandidates() []scriptCandidate {
+	var candidates []scriptCandidate
+	for _, subcmd := range c.cmd.Commands {
+		candidates = append(candidates, scriptCandidate{
+			name:        subcmd.Name,
+			description: strings.SplitN(subcmd.Description, "\n", 2)[0],
+		})
+	}
+	return candidates
+}
+
+// flagCandidates returns the candidates for the names and aliases of the flags
+// of the command.
+func (c scriptCommand) flagCandidates() []scriptCandidate {
+	var candidates []scriptCandidate
+	for _, flag := range c.flags {
+		names := append([]string{flag.Name}, flag.Aliases...)
+		for _, name := range append(names, flag.negatedNames()...) {
+			candidates = append(candidates, scriptCandidate{
+				name:        name,
+				description: flag.Description,
+			})
+		}
+	}
+	return candidates
+}
+
+// tooltip returns the description of the candidate, or its name if it doesn't
+// have a description.
+func (c scriptCandidate) tooltip() string {
+	if c.description == "" {
+		return c.name
+	}
+	return c.description
+}
+
+// zshCandidates returns the given candidates in the format used by _describe
+// (i.e. name:description).
+func zshCandidates(candidates []scriptCandidate) []string {
+	var values []string
+	for _, c := range candidates {
+		value := strings.Replace(c.name, ":", "\\:", -1)
+		if c.description != "" {
+			value += ":" + c.description
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// scriptIdentifier returns the given name with all characters that cannot be
+// used in function names replaced by underscores.
+func scriptIdentifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// shellQuote quotes the given string for bash and zsh.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// shellQuoteAll quotes all the given strings for bash and zsh.
+func shellQuoteAll(ss []string) []string {
+	var quoted []string
+	for _, s := range ss {
+		quoted = append(quoted, shellQuote(s))
+	}
+	return quoted
+}
+
+// fishQuote quotes the given string for fish.
+func fishQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
+
+// fishQuoteAll quotes all the given strings for fish.
+func fishQuoteAll(ss []string) []string {
+	var quoted []string
+	for _, s := range ss {
+		quoted = append(quoted, fishQuote(s))
+	}
+	return quoted
+}
+
+// powerShellQuote quotes the given string for PowerShell.
+func powerShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
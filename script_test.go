@@ -0,0 +1,104 @@
+package mojo_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ravernkoh/mojo"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestCompletion(t *testing.T) {
+	conf := mojo.Config{
+		Root: mojo.CommandConfig{
+			Name: "tldr",
+			Commands: []mojo.CommandConfig{
+				{
+					Name:        "add",
+					Description: "Add a page.\nThe page is added to the local cache.",
+					Complete:    mojo.CompleteFiles(".md"),
+					Flags: []mojo.FlagConfig{
+						{
+							Name:        "--force",
+							Aliases:     []string{"-f"},
+							Bool:        true,
+							Description: "Don't ask for confirmation.",
+						},
+						{
+							Name:        "--platform",
+							Description: "Set the platform of the page.",
+						},
+					},
+				},
+				{
+					Name:    "cache",
+					Aliases: []string{"c"},
+					Commands: []mojo.CommandConfig{
+						{
+							Name:        "clear",
+							Description: "Clear the local cache.",
+						},
+					},
+				},
+			},
+			Flags: []mojo.FlagConfig{
+				{
+					Name:        "--verbose",
+					Aliases:     []string{"-v"},
+					Bool:        true,
+					Description: "Enable verbose output.",
+				},
+				{
+					Name:        "--level",
+					Aliases:     []string{"-l"},
+					Description: "Set the user's level.",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		script func(mojo.Config) string
+	}{
+		{
+			name:   "completion.bash",
+			script: mojo.BashCompletion,
+		},
+		{
+			name:   "completion.zsh",
+			script: mojo.ZshCompletion,
+		},
+		{
+			name:   "completion.fish",
+			script: mojo.FishCompletion,
+		},
+		{
+			name:   "completion.ps1",
+			script: mojo.PowerShellCompletion,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.script(conf)
+
+			golden := filepath.Join("testdata", test.name+".golden")
+			if *update {
+				if err := ioutil.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("want script\n%s\ngot script\n%s", want, got)
+			}
+		})
+	}
+}
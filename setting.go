@@ -0,0 +1,59 @@
+package mojo
+
+import (
+	"fmt"
+)
+
+// Setting represents the resolved value of a flag, along with where the value
+// came from.
+type Setting struct {
+	Flag FlagObject
+}
+
+// Origin returns where the value of the setting came from, which is one of
+// default, env, the file name and line number (e.g. config.ini:3) or the index
+// of the argument (e.g. arg 2).
+func (s Setting) Origin() string {
+	switch s.Flag.Source {
+	case SourceArgument:
+		return fmt.Sprintf("arg %d", s.Flag.Index)
+	case SourceFile:
+		return fmt.Sprintf("%s:%d", s.Flag.File, s.Flag.Line)
+	}
+	return s.Flag.Source.String()
+}
+
+// Settings returns the settings of all the flags in order.
+//
+// Since flags are only added from the environment, configuration files and
+// default values if they weren't passed, each setting is already resolved.
+// Check Files in Config for more information.
+func (objs Objects) Settings() []Setting {
+	var settings []Setting
+	for _, obj := range objs {
+		if flagObj, ok := obj.(FlagObject); ok {
+			settings = append(settings, Setting{Flag: flagObj})
+		}
+	}
+	return settings
+}
+
+// Setting returns the setting of the flag with the given name or alias.
+//
+// An error will be returned if there are no flags found or if there is more
+// than one flag found.
+func (objs Objects) Setting(name string) (Setting, error) {
+	if _, err := objs.Flag(name); err != nil {
+		return Setting{}, err
+	}
+
+	for _, setting := range objs.Settings() {
+		if setting.Flag.hasName(name) {
+			return setting, nil
+		}
+	}
+	return Setting{}, FlagError{
+		Name: name,
+		Err:  ErrFlagNotFound,
+	}
+}
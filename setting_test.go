@@ -0,0 +1,151 @@
+package mojo_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ravernkoh/mojo"
+)
+
+func TestObjects_Settings(t *testing.T) {
+	conf := mojo.Config{
+		AllowMutipleFlags: true,
+		LookupEnv: func(key string) (string, bool) {
+			if key == "TLDR_PAGER" {
+				return "less", true
+			}
+			return "", false
+		},
+		Files: []mojo.File{
+			{
+				Name: "/etc/tldr.ini",
+				Values: []mojo.FileValue{
+					{Key: "format", Value: "text", Line: 1},
+					{Key: "color", Value: "never", Line: 2},
+				},
+			},
+			{
+				Name: "tldr.ini",
+				Values: []mojo.FileValue{
+					{Key: "format", Value: "json", Line: 4},
+					{Key: "pager", Value: "more", Line: 5},
+				},
+			},
+		},
+		Root: mojo.CommandConfig{
+			Name: "tldr",
+			Flags: []mojo.FlagConfig{
+				{Name: "--format"},
+				{Name: "--color"},
+				{Name: "--pager", Env: "TLDR_PAGER"},
+				{Name: "--level", Default: "1"},
+			},
+		},
+	}
+
+	objs, err := mojo.Parse(conf, []string{"tldr", "nmap", "-vl", "5", "--", "-a"})
+	if err != nil {
+		t.Fatalf("want err %v, got err %v", nil, err)
+	}
+
+	var got []string
+	for _, setting := range objs.Settings() {
+		got = append(got, fmt.Sprintf("%s=%s (%s)", setting.Flag.Name, setting.Flag.Value, setting.Origin()))
+	}
+	want := []string{
+		"-v= (arg 2)",
+		"-l=5 (arg 2)",
+		"--format=json (tldr.ini:4)",
+		"--color=never (/etc/tldr.ini:2)",
+		"--pager=less (env)",
+		"--level=1 (default)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want settings %v, got settings %v", want, got)
+	}
+}
+
+func TestObjects_Setting(t *testing.T) {
+	conf := mojo.Config{
+		Root: mojo.CommandConfig{
+			Name: "tool",
+			Commands: []mojo.CommandConfig{
+				{Name: "sub"},
+			},
+			Flags: []mojo.FlagConfig{
+				{Name: "-v", Bool: true, Count: true},
+				{Name: "--level"},
+			},
+		},
+	}
+
+	type args struct {
+		args []string
+		name string
+	}
+
+	type rets struct {
+		origin string
+		err    error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrFlagNotFound",
+			args: args{
+				args: []string{"tool", "-v"},
+				name: "--level",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: flag not found: --level"),
+			},
+		},
+		{
+			name: "CountingFlags",
+			args: args{
+				args: []string{"tool", "-v", "-v", "--level", "5"},
+				name: "--level",
+			},
+			want: rets{
+				origin: "arg 3",
+			},
+		},
+		{
+			name: "CombinedFlagValueAfterSubcommand",
+			args: args{
+				args: []string{"tool", "a", "sub", "b", "--level=5"},
+				name: "--level",
+			},
+			want: rets{
+				origin: "arg 4",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objs, err := mojo.Parse(conf, test.args.args)
+			if err != nil {
+				t.Fatalf("want err %v, got err %v", nil, err)
+			}
+
+			var got rets
+			setting, err := objs.Setting(test.args.name)
+			got.err = err
+			if err == nil {
+				got.origin = setting.Origin()
+			}
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if got.origin != test.want.origin {
+				t.Errorf("want origin %v, got origin %v", test.want.origin, got.origin)
+			}
+		})
+	}
+}
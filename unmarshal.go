@@ -0,0 +1,430 @@
+package mojo
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Unmarshal stores the values of the given objects in the struct pointed to by
+// v, based on the mojo tags of its fields.
+//
+// The tag of a flag field contains the name of the flag, followed by its
+// aliases, separated by commas (e.g. `mojo:"--level,-l"`). Supported field
+// types are strings, bools, ints, uints, floats, time.Duration,
+// map[string]string and slices of those, where slices will contain the values
+// of repeated flags in order.
+//
+// The tag of a struct field, or a pointer to a struct field, contains the name
+// of a subcommand (e.g. `mojo:"add"`). The fields of the struct will then be
+// filled using the objects of the subcommand. If the field is a pointer, it
+// will only be set if the subcommand was parsed.
+//
+// An int flag field can have the count option after its names (e.g.
+// `mojo:"--verbose,-v,count"`), which indicates that it is a counting flag.
+// StructConfig then configures the flag with Count in FlagConfig.
+//
+// Fields without tags and unexported fields are ignored, and fields of flags
+// which weren't parsed are left untouched. Fields of unsupported types result
+// in an unsupported type error.
+func Unmarshal(objs Objects, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidStruct
+	}
+
+	segs := commandSegments(objs)
+	if len(segs) == 0 {
+		return nil
+	}
+
+	_, err := unmarshalCommand(rv.Elem(), segs)
+	return err
+}
+
+// StructConfig returns the configuration derived from the struct pointed to by
+// v, with the root command having the given name.
+//
+// Check Unmarshal for more information on the tags used.
+func StructConfig(name string, v interface{}) (Config, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return Config{}, ErrInvalidStruct
+	}
+
+	root, err := structCommandConfig(name, rv.Elem().Type())
+	if err != nil {
+		return Config{}, err
+	}
+	return Config{Root: root}, nil
+}
+
+// structCommandConfig returns the command configuration with the given name
+// derived from the given struct type.
+func structCommandConfig(name string, t reflect.Type) (CommandConfig, error) {
+	cmd := CommandConfig{Name: name}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag, ok := parseFieldTag(field)
+		if !ok {
+			continue
+		}
+
+		if tag.command {
+			subcmd, err := structCommandConfig(tag.names[0], indirectType(field.Type))
+			if err != nil {
+				return CommandConfig{}, err
+			}
+			cmd.Commands = append(cmd.Commands, subcmd)
+			continue
+		}
+
+		if !isValueType(field.Type) {
+			return CommandConfig{}, FieldError{
+				Name: field.Name,
+				Err:  ErrUnsupportedType,
+			}
+		}
+
+		flag := FlagConfig{
+			Name: tag.names[0],
+			Bool: elemType(field.Type).Kind() == reflect.Bool,
+		}
+		if tag.isCounter(field.Type) {
+			flag.Bool = true
+			flag.Count = true
+		}
+		if len(tag.names) > 1 {
+			flag.Aliases = tag.names[1:]
+		}
+		cmd.Flags = append(cmd.Flags, flag)
+	}
+
+	return cmd, nil
+}
+
+// unmarshalCommand stores the values of the given command segments in the
+// given struct and returns the flags which weren't stored.
+//
+// Flags which weren't stored by a subcommand are passed up to its parent
+// command, since they might be configured in the parent.
+func unmarshalCommand(rv reflect.Value, segs [][]Object) ([]FlagObject, error) {
+	var flagObjs []FlagObject
+
+	// Collect the flags of this command.
+	for _, obj := range segs[0] {
+		if flagObj, ok := obj.(FlagObject); ok {
+			flagObjs = append(flagObjs, flagObj)
+		}
+	}
+
+	// Unmarshal the subcommand first, so that precedence is given to the
+	// subcommand for flags that are configured in both.
+	if len(segs) > 1 {
+		name := segs[1][0].(CommandObject).commandName()
+
+		subFlagObjs, err := unmarshalSubcommand(rv, name, segs[1:])
+		if err != nil {
+			return nil, err
+		}
+		flagObjs = append(flagObjs, subFlagObjs...)
+	}
+
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag, ok := parseFieldTag(field)
+		if !ok || tag.command {
+			continue
+		}
+		if !isValueType(field.Type) {
+			return nil, FieldError{
+				Name: field.Name,
+				Err:  ErrUnsupportedType,
+			}
+		}
+
+		// Split the flags into those of this field and the rest.
+		var fieldFlagObjs, restFlagObjs []FlagObject
+		for _, flagObj := range flagObjs {
+			if tag.hasFlag(flagObj) {
+				fieldFlagObjs = append(fieldFlagObjs, flagObj)
+			} else {
+				restFlagObjs = append(restFlagObjs, flagObj)
+			}
+		}
+		flagObjs = restFlagObjs
+
+		if len(fieldFlagObjs) == 0 {
+			continue
+		}
+		if err := setField(rv.Field(i), fieldFlagObjs); err != nil {
+			return nil, err
+		}
+	}
+
+	return flagObjs, nil
+}
+
+// unmarshalSubcommand stores the values of the given command segments in the
+// field of the subcommand with the given name and returns the flags which
+// weren't stored.
+//
+// If there is no such field, then all the flags of the segments are returned.
+func unmarshalSubcommand(rv reflect.Value, name string, segs [][]Object) ([]FlagObject, error) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := parseFieldTag(t.Field(i))
+		if !ok || !tag.command || tag.names[0] != name {
+			continue
+		}
+
+		// Allocate the struct if the field is a pointer.
+		fieldValue := rv.Field(i)
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		return unmarshalCommand(fieldValue, segs)
+	}
+
+	var flagObjs []FlagObject
+	for _, seg := range segs {
+		for _, obj := range seg {
+			if flagObj, ok := obj.(FlagObject); ok {
+				flagObjs = append(flagObjs, flagObj)
+			}
+		}
+	}
+	return flagObjs, nil
+}
+
+// setField sets the value of the given field using the given flags.
+func setField(rv reflect.Value, flagObjs []FlagObject) error {
+	// Maps and slices can contain the values of many flags.
+	switch rv.Kind() {
+	case reflect.Map:
+		values := make(map[string]string)
+		for _, flagObj := range flagObjs {
+			if err := stringMapValue(flagObj, values); err != nil {
+				return err
+			}
+		}
+		rv.Set(reflect.ValueOf(values))
+		return nil
+	case reflect.Slice:
+		values := reflect.MakeSlice(rv.Type(), len(flagObjs), len(flagObjs))
+		for i, flagObj := range flagObjs {
+			if err := setValue(values.Index(i), flagObj); err != nil {
+				return err
+			}
+		}
+		rv.Set(values)
+		return nil
+	}
+
+	// The last bool flag is used (e.g. --color --no-color).
+	if rv.Kind() == reflect.Bool {
+		return setValue(rv, flagObjs[len(flagObjs)-1])
+	}
+
+	// Counting flags are counted (e.g. -vvv).
+	if flagObjs[0].Counter && rv.Kind() == reflect.Int {
+		count, err := countValue(flagObjs)
+		if err != nil {
+			return err
+		}
+		rv.SetInt(int64(count))
+		return nil
+	}
+
+	if len(flagObjs) > 1 {
+		return FlagError{
+			Name: flagObjs[0].Name,
+			Err:  ErrUnexpectedArrayFlag,
+		}
+	}
+	return setValue(rv, flagObjs[0])
+}
+
+// setValue sets the given value using the value of the given flag.
+func setValue(rv reflect.Value, obj FlagObject) error {
+	if rv.Type() == durationType {
+		value, err := durationValue(obj)
+		if err != nil {
+			return err
+		}
+		rv.SetInt(int64(value))
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(obj.Value)
+	case reflect.Bool:
+		value, err := boolValue(obj)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if obj.Bool {
+			return invalidValueError(obj)
+		}
+		value, err := strconv.ParseInt(obj.Value, 10, rv.Type().Bits())
+		if err != nil {
+			return invalidValueError(obj)
+		}
+		rv.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if obj.Bool {
+			return invalidValueError(obj)
+		}
+		value, err := strconv.ParseUint(obj.Value, 10, rv.Type().Bits())
+		if err != nil {
+			return invalidValueError(obj)
+		}
+		rv.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		if obj.Bool {
+			return invalidValueError(obj)
+		}
+		value, err := strconv.ParseFloat(obj.Value, rv.Type().Bits())
+		if err != nil {
+			return invalidValueError(obj)
+		}
+		rv.SetFloat(value)
+	default:
+		return ErrUnsupportedType
+	}
+	return nil
+}
+
+// fieldTag represents a parsed mojo tag.
+type fieldTag struct {
+	// names contains the name of the flag or command, followed by the
+	// aliases of the flag.
+	names []string
+
+	// options contains the rest of the tag.
+	options []string
+
+	// command indicates whether the field is a subcommand.
+	command bool
+}
+
+// parseFieldTag parses the mojo tag of the given field.
+//
+// Returns false if the field doesn't have a mojo tag or is unexported.
+func parseFieldTag(field reflect.StructField) (fieldTag, bool) {
+	value, ok := field.Tag.Lookup("mojo")
+	if !ok || value == "" || value == "-" || field.PkgPath != "" {
+		return fieldTag{}, false
+	}
+
+	var tag fieldTag
+	parts := strings.Split(value, ",")
+
+	// Structs are subcommands, with the first part as the name. Structs
+	// with the names of flags (e.g. time.Time) are left as unsupported
+	// flag fields.
+	if indirectType(field.Type).Kind() == reflect.Struct && !strings.HasPrefix(parts[0], "-") {
+		tag.command = true
+		tag.names = parts[:1]
+		tag.options = parts[1:]
+		return tag, true
+	}
+
+	for _, part := range parts {
+		if strings.HasPrefix(part, "-") {
+			tag.names = append(tag.names, part)
+		} else {
+			tag.options = append(tag.options, part)
+		}
+	}
+	if len(tag.names) == 0 {
+		return fieldTag{}, false
+	}
+	return tag, true
+}
+
+// hasFlag returns whether the given flag belongs to the field.
+func (tag fieldTag) hasFlag(obj FlagObject) bool {
+	for _, name := range tag.names {
+		if obj.hasName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOption returns whether the given option is in the tag.
+func (tag fieldTag) hasOption(option string) bool {
+	for _, opt := range tag.options {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isCounter returns whether the field of the given type with the tag is a
+// counting flag.
+func (tag fieldTag) isCounter(t reflect.Type) bool {
+	return tag.hasOption("count") && t.Kind() == reflect.Int
+}
+
+// commandSegments splits the given objects into segments, with each segment
+// starting with a command.
+func commandSegments(objs Objects) [][]Object {
+	var segs [][]Object
+	for _, obj := range objs {
+		if _, ok := obj.(CommandObject); ok || len(segs) == 0 {
+			segs = append(segs, nil)
+		}
+		segs[len(segs)-1] = append(segs[len(segs)-1], obj)
+	}
+	return segs
+}
+
+// durationType is the type of time.Duration.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// isValueType returns whether the given type can be used to store the values of
+// flags.
+func isValueType(t reflect.Type) bool {
+	if t.Kind() == reflect.Map {
+		return t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.String
+	}
+	switch elemType(t).Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// elemType returns the element type of the given type if it is a slice.
+func elemType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Slice {
+		return t.Elem()
+	}
+	return t
+}
+
+// indirectType returns the element type of the given type if it is a pointer.
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,303 @@
+package mojo_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ravernkoh/mojo"
+)
+
+type unmarshalOptions struct {
+	Verbose bool              `mojo:"--verbose,-v"`
+	Level   int               `mojo:"--level,-l"`
+	Timeout time.Duration     `mojo:"--timeout"`
+	Tags    []string          `mojo:"--tag"`
+	Labels  map[string]string `mojo:"--label"`
+	Ignored string
+	ignored string `mojo:"--ignored"`
+
+	Add *unmarshalAddOptions `mojo:"add"`
+}
+
+type unmarshalAddOptions struct {
+	Level uint   `mojo:"--level"`
+	Name  string `mojo:"--name,-n"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	type args struct {
+		objs mojo.Objects
+	}
+
+	type rets struct {
+		opts unmarshalOptions
+		err  error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrInvalidValue",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--level", Value: "high"},
+				},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid value: --level"),
+			},
+		},
+		{
+			name: "ErrUnexpectedArrayFlag",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--level", Value: "1"},
+					mojo.FlagObject{Name: "-l", Value: "2", Canonical: "--level", Aliases: []string{"-l"}},
+				},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: unexpected array flag: --level"),
+			},
+		},
+		{
+			name: "Flags",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "-v", Canonical: "--verbose", Aliases: []string{"-v"}, Bool: true},
+					mojo.FlagObject{Name: "--level", Value: "5", Canonical: "--level", Aliases: []string{"-l"}},
+					mojo.FlagObject{Name: "--timeout", Value: "1m"},
+					mojo.FlagObject{Name: "--tag", Value: "a"},
+					mojo.FlagObject{Name: "--label", Value: "a=1,b=2"},
+					mojo.FlagObject{Name: "--tag", Value: "b,c"},
+					mojo.FlagObject{Name: "--ignored", Value: "a"},
+					mojo.ArgumentObject{Value: "nmap"},
+				},
+			},
+			want: rets{
+				opts: unmarshalOptions{
+					Verbose: true,
+					Level:   5,
+					Timeout: time.Minute,
+					Tags:    []string{"a", "b,c"},
+					Labels:  map[string]string{"a": "1", "b": "2"},
+				},
+			},
+		},
+		{
+			name: "SubcommandAndFlags",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.CommandObject{Name: "add"},
+					mojo.FlagObject{Name: "--level", Value: "5"},
+					mojo.FlagObject{Name: "-v", Bool: true},
+					mojo.FlagObject{Name: "-n", Value: "nmap"},
+				},
+			},
+			want: rets{
+				opts: unmarshalOptions{
+					Verbose: true,
+					Add: &unmarshalAddOptions{
+						Level: 5,
+						Name:  "nmap",
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.err = mojo.Unmarshal(test.args.objs, &got.opts)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if test.want.err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.opts, test.want.opts) {
+				t.Errorf("want opts %+v, got opts %+v", test.want.opts, got.opts)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_UnsupportedType(t *testing.T) {
+	type args struct {
+		objs mojo.Objects
+		v    interface{}
+	}
+
+	type rets struct {
+		err error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "Pointer",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--name", Value: "nmap"},
+				},
+				v: &struct {
+					Name *string `mojo:"--name"`
+				}{},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: unsupported type: Name"),
+			},
+		},
+		{
+			name: "Struct",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+				},
+				v: &struct {
+					Since time.Time `mojo:"--since"`
+				}{},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: unsupported type: Since"),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.err = mojo.Unmarshal(test.args.objs, test.args.v)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+			}
+		})
+	}
+}
+
+func TestStructConfig(t *testing.T) {
+	type args struct {
+		name string
+		v    interface{}
+	}
+
+	type rets struct {
+		conf mojo.Config
+		err  error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrInvalidStruct",
+			args: args{
+				name: "tldr",
+				v:    unmarshalOptions{},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid struct"),
+			},
+		},
+		{
+			name: "ErrUnsupportedType",
+			args: args{
+				name: "tldr",
+				v: &struct {
+					Levels map[string]int `mojo:"--level"`
+				}{},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: unsupported type: Levels"),
+			},
+		},
+		{
+			name: "SubcommandAndFlags",
+			args: args{
+				name: "tldr",
+				v:    &unmarshalOptions{},
+			},
+			want: rets{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "tldr",
+						Commands: []mojo.CommandConfig{
+							{
+								Name: "add",
+								Flags: []mojo.FlagConfig{
+									{Name: "--level"},
+									{Name: "--name", Aliases: []string{"-n"}},
+								},
+							},
+						},
+						Flags: []mojo.FlagConfig{
+							{Name: "--verbose", Aliases: []string{"-v"}, Bool: true},
+							{Name: "--level", Aliases: []string{"-l"}},
+							{Name: "--timeout"},
+							{Name: "--tag"},
+							{Name: "--label"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "CountingFlags",
+			args: args{
+				name: "ls",
+				v: &struct {
+					Verbose int `mojo:"--verbose,-v,count"`
+				}{},
+			},
+			want: rets{
+				conf: mojo.Config{
+					Root: mojo.CommandConfig{
+						Name: "ls",
+						Flags: []mojo.FlagConfig{
+							{Name: "--verbose", Aliases: []string{"-v"}, Bool: true, Count: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ErrUnsupportedTypeStruct",
+			args: args{
+				name: "tldr",
+				v: &struct {
+					Since time.Time `mojo:"--since"`
+				}{},
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: unsupported type: Since"),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.conf, got.err = mojo.StructConfig(test.args.name, test.args.v)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if !reflect.DeepEqual(got.conf, test.want.conf) {
+				t.Errorf("want conf %+v, got conf %+v", test.want.conf, got.conf)
+			}
+		})
+	}
+}
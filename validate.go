@@ -0,0 +1,136 @@
+package mojo
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// ValidateRange returns a validation function which checks that the value is
+// a number between the given minimum and maximum, inclusive.
+//
+// Check Validate in FlagConfig for more information.
+func ValidateRange(min float64, max float64) func(value string) error {
+	return func(value string) error {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return ErrInvalidValue
+		}
+		if n < min || n > max {
+			return ErrOutOfRange
+		}
+		return nil
+	}
+}
+
+// ValidatePattern returns a validation function which checks that the value
+// matches the given regular expression. It panics if the expression cannot be
+// parsed.
+//
+// Check Validate in FlagConfig for more information.
+func ValidatePattern(pattern string) func(value string) error {
+	re := regexp.MustCompile(pattern)
+	return func(value string) error {
+		if !re.MatchString(value) {
+			return ErrPatternMismatch
+		}
+		return nil
+	}
+}
+
+// validateFlag checks the value of the given flag against the given
+// configuration. Implicit values aren't checked, since they weren't passed.
+func validateFlag(flag FlagConfig, obj FlagObject) error {
+	if obj.Bool || obj.ImplicitValue {
+		return nil
+	}
+
+	if len(flag.Choices) > 0 && !containsString(flag.Choices, obj.Value) {
+		return FlagError{
+			Name:        obj.Name,
+			Err:         ErrInvalidChoice,
+			Choices:     flag.Choices,
+			Suggestions: suggestions(obj.Value, flag.Choices),
+		}
+	}
+
+	if flag.Validate != nil {
+		if err := flag.Validate(obj.Value); err != nil {
+			return FlagError{
+				Name: obj.Name,
+				Err:  err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// suggestions returns the given candidates which are close enough to the given
+// value to be suggested, with the closest first.
+func suggestions(value string, candidates []string) []string {
+	// Allow more edits for longer values, but never enough to replace the
+	// candidate entirely.
+	max := len(value)/3 + 1
+
+	var (
+		closest   []string
+		distances = make(map[string]int)
+	)
+	for _, candidate := range candidates {
+		if _, ok := distances[candidate]; ok {
+			continue
+		}
+		d := editDistance(value, candidate)
+		distances[candidate] = d
+		if d <= max && d < len(candidate) {
+			closest = append(closest, candidate)
+		}
+	}
+
+	sort.SliceStable(closest, func(i, j int) bool {
+		return distances[closest[i]] < distances[closest[j]]
+	})
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between the given strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, minInt(curr[j-1]+1, prev[j-1]+cost))
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// minInt returns the smaller of the given integers.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// containsString returns whether the given strings contain the given string.
+func containsString(ss []string, s string) bool {
+	for _, t := range ss {
+		if t == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package mojo
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bool returns the value of the flag with the given name or alias as a bool.
+//
+// A flag passed without a value (e.g. --verbose) is true unless it was
+// negated (e.g. --no-verbose), while a flag passed with a value (e.g.
+// --verbose=false) is parsed using strconv.ParseBool. If the flag was passed
+// more than once (e.g. --color --no-color), the last one is used.
+func (objs Objects) Bool(name string) (bool, error) {
+	flagObjs := objs.ArrayFlag(name)
+	if len(flagObjs) == 0 {
+		return false, FlagError{
+			Name: name,
+			Err:  ErrFlagNotFound,
+		}
+	}
+	return boolValue(flagObjs[len(flagObjs)-1])
+}
+
+// Int returns the value of the flag with the given name or alias as an int.
+func (objs Objects) Int(name string) (int, error) {
+	obj, err := objs.Flag(name)
+	if err != nil {
+		return 0, err
+	}
+	return intValue(obj)
+}
+
+// Float returns the value of the flag with the given name or alias as a
+// float64.
+func (objs Objects) Float(name string) (float64, error) {
+	obj, err := objs.Flag(name)
+	if err != nil {
+		return 0, err
+	}
+	return floatValue(obj)
+}
+
+// Duration returns the value of the flag with the given name or alias as a
+// time.Duration, parsed using time.ParseDuration.
+func (objs Objects) Duration(name string) (time.Duration, error) {
+	obj, err := objs.Flag(name)
+	if err != nil {
+		return 0, err
+	}
+	return durationValue(obj)
+}
+
+// StringSlice returns the values of the flags with the given name or alias.
+//
+// Each flag can contain multiple values separated by commas (e.g.
+// --tag a,b --tag c will result in [a b c]). If there are no flags found, an
+// empty slice is returned.
+func (objs Objects) StringSlice(name string) ([]string, error) {
+	var values []string
+	for _, obj := range objs.ArrayFlag(name) {
+		objValues, err := stringSliceValue(obj)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, objValues...)
+	}
+	return values, nil
+}
+
+// StringMap returns the values of the flags with the given name or alias as
+// a map.
+//
+// Each flag can contain multiple key and value pairs separated by commas (e.g.
+// --label a=1,b=2 --label c=3). If a key is repeated, the last value is used.
+// If there are no flags found, an empty map is returned.
+func (objs Objects) StringMap(name string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, obj := range objs.ArrayFlag(name) {
+		if err := stringMapValue(obj, values); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// Count returns the number of times the flag with the given name or alias was
+// passed.
+//
+// A flag passed without a value (e.g. -v) adds one to the count, while a flag
+// passed with a value (e.g. --verbose=3) sets the count to its value. A
+// negated flag (e.g. --no-verbose) resets the count to zero. If there are no
+// flags found, zero is returned.
+func (objs Objects) Count(name string) (int, error) {
+	return countValue(objs.ArrayFlag(name))
+}
+
+// countValue returns the number of times the given flags were passed.
+func countValue(objs []FlagObject) (int, error) {
+	var count int
+	for _, obj := range objs {
+		switch {
+		case obj.Negated:
+			count = 0
+		case obj.Bool:
+			count++
+		default:
+			value, err := intValue(obj)
+			if err != nil {
+				return 0, err
+			}
+			count = value
+		}
+	}
+	return count, nil
+}
+
+// boolValue returns the value of the given flag as a bool.
+func boolValue(obj FlagObject) (bool, error) {
+	if obj.Bool {
+		return !obj.Negated, nil
+	}
+	value, err := strconv.ParseBool(obj.Value)
+	if err != nil {
+		return false, invalidValueError(obj)
+	}
+	return value, nil
+}
+
+// intValue returns the value of the given flag as an int.
+func intValue(obj FlagObject) (int, error) {
+	if obj.Bool {
+		return 0, invalidValueError(obj)
+	}
+	value, err := strconv.Atoi(obj.Value)
+	if err != nil {
+		return 0, invalidValueError(obj)
+	}
+	return value, nil
+}
+
+// floatValue returns the value of the given flag as a float64.
+func floatValue(obj FlagObject) (float64, error) {
+	if obj.Bool {
+		return 0, invalidValueError(obj)
+	}
+	value, err := strconv.ParseFloat(obj.Value, 64)
+	if err != nil {
+		return 0, invalidValueError(obj)
+	}
+	return value, nil
+}
+
+// durationValue returns the value of the given flag as a time.Duration.
+func durationValue(obj FlagObject) (time.Duration, error) {
+	if obj.Bool {
+		return 0, invalidValueError(obj)
+	}
+	value, err := time.ParseDuration(obj.Value)
+	if err != nil {
+		return 0, invalidValueError(obj)
+	}
+	return value, nil
+}
+
+// stringSliceValue returns the comma separated values of the given flag.
+func stringSliceValue(obj FlagObject) ([]string, error) {
+	if obj.Bool {
+		return nil, invalidValueError(obj)
+	}
+	return strings.Split(obj.Value, ","), nil
+}
+
+// stringMapValue adds the comma separated key and value pairs of the given
+// flag into the given map.
+func stringMapValue(obj FlagObject, values map[string]string) error {
+	pairs, err := stringSliceValue(obj)
+	if err != nil {
+		return err
+	}
+	for _, pair := range pairs {
+		i := strings.Index(pair, "=")
+		if i == -1 {
+			return invalidValueError(obj)
+		}
+		values[pair[:i]] = pair[i+1:]
+	}
+	return nil
+}
+
+// invalidValueError returns the error for when the value of the given flag is
+// invalid.
+func invalidValueError(obj FlagObject) error {
+	return FlagError{
+		Name: obj.Name,
+		Err:  ErrInvalidValue,
+	}
+}
@@ -0,0 +1,520 @@
+package mojo_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ravernkoh/mojo"
+)
+
+func TestObjects_Bool(t *testing.T) {
+	type args struct {
+		objs mojo.Objects
+		name string
+	}
+
+	type rets struct {
+		value bool
+		err   error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrFlagNotFound",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+				},
+				name: "--verbose",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: flag not found: --verbose"),
+			},
+		},
+		{
+			name: "ErrInvalidValue",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--verbose", Value: "maybe"},
+				},
+				name: "--verbose",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid value: --verbose"),
+			},
+		},
+		{
+			name: "BoolFlag",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--verbose", Bool: true},
+				},
+				name: "--verbose",
+			},
+			want: rets{
+				value: true,
+			},
+		},
+		{
+			name: "CombinedFlagValues",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--verbose", Value: "false", CombinedFlagValues: true},
+				},
+				name: "--verbose",
+			},
+			want: rets{
+				value: false,
+			},
+		},
+		{
+			name: "NegatedFlag",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--color", Canonical: "--color", Bool: true},
+					mojo.FlagObject{Name: "--no-color", Canonical: "--color", Bool: true, Negated: true},
+				},
+				name: "--color",
+			},
+			want: rets{
+				value: false,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.value, got.err = test.args.objs.Bool(test.args.name)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if got.value != test.want.value {
+				t.Errorf("want value %v, got value %v", test.want.value, got.value)
+			}
+		})
+	}
+}
+
+func TestObjects_Int(t *testing.T) {
+	type args struct {
+		objs mojo.Objects
+		name string
+	}
+
+	type rets struct {
+		value int
+		err   error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrInvalidValue",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--level", Value: "high"},
+				},
+				name: "--level",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid value: --level"),
+			},
+		},
+		{
+			name: "ErrInvalidValueBoolFlag",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--level", Bool: true},
+				},
+				name: "--level",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid value: --level"),
+			},
+		},
+		{
+			name: "Alias",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "-l", Value: "5", Canonical: "--level", Aliases: []string{"-l"}},
+				},
+				name: "--level",
+			},
+			want: rets{
+				value: 5,
+			},
+		},
+		{
+			name: "Default",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--level", Value: "1", Canonical: "--level", Source: mojo.SourceDefault},
+				},
+				name: "--level",
+			},
+			want: rets{
+				value: 1,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.value, got.err = test.args.objs.Int(test.args.name)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if got.value != test.want.value {
+				t.Errorf("want value %v, got value %v", test.want.value, got.value)
+			}
+		})
+	}
+}
+
+func TestObjects_Float(t *testing.T) {
+	type args struct {
+		objs mojo.Objects
+		name string
+	}
+
+	type rets struct {
+		value float64
+		err   error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrInvalidValue",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--ratio", Value: "half"},
+				},
+				name: "--ratio",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid value: --ratio"),
+			},
+		},
+		{
+			name: "ErrInvalidValueBoolFlag",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--ratio", Bool: true},
+				},
+				name: "--ratio",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid value: --ratio"),
+			},
+		},
+		{
+			name: "Alias",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "-r", Value: "0.5", Canonical: "--ratio", Aliases: []string{"-r"}},
+				},
+				name: "--ratio",
+			},
+			want: rets{
+				value: 0.5,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.value, got.err = test.args.objs.Float(test.args.name)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if got.value != test.want.value {
+				t.Errorf("want value %v, got value %v", test.want.value, got.value)
+			}
+		})
+	}
+}
+
+func TestObjects_Count(t *testing.T) {
+	type args struct {
+		objs mojo.Objects
+		name string
+	}
+
+	type rets struct {
+		value int
+		err   error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrInvalidValue",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--verbose", Value: "high", Counter: true},
+				},
+				name: "--verbose",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid value: --verbose"),
+			},
+		},
+		{
+			name: "NoFlags",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+				},
+				name: "--verbose",
+			},
+			want: rets{
+				value: 0,
+			},
+		},
+		{
+			name: "CountingFlags",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--verbose", Value: "2", Canonical: "--verbose", Counter: true, CombinedFlagValues: true},
+					mojo.FlagObject{Name: "-v", Canonical: "--verbose", Bool: true, Counter: true},
+					mojo.FlagObject{Name: "-v", Canonical: "--verbose", Bool: true, Counter: true},
+				},
+				name: "--verbose",
+			},
+			want: rets{
+				value: 4,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.value, got.err = test.args.objs.Count(test.args.name)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if got.value != test.want.value {
+				t.Errorf("want value %v, got value %v", test.want.value, got.value)
+			}
+		})
+	}
+}
+
+func TestObjects_Duration(t *testing.T) {
+	type args struct {
+		objs mojo.Objects
+		name string
+	}
+
+	type rets struct {
+		value time.Duration
+		err   error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrInvalidValue",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--timeout", Value: "often"},
+				},
+				name: "--timeout",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid value: --timeout"),
+			},
+		},
+		{
+			name: "Flag",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--timeout", Value: "1m30s"},
+				},
+				name: "--timeout",
+			},
+			want: rets{
+				value: 90 * time.Second,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.value, got.err = test.args.objs.Duration(test.args.name)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if got.value != test.want.value {
+				t.Errorf("want value %v, got value %v", test.want.value, got.value)
+			}
+		})
+	}
+}
+
+func TestObjects_StringSlice(t *testing.T) {
+	type args struct {
+		objs mojo.Objects
+		name string
+	}
+
+	type rets struct {
+		values []string
+		err    error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "None",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+				},
+				name: "--tag",
+			},
+			want: rets{},
+		},
+		{
+			name: "ErrInvalidValue",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--tag", Bool: true},
+				},
+				name: "--tag",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid value: --tag"),
+			},
+		},
+		{
+			name: "Many",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--tag", Value: "a,b"},
+					mojo.FlagObject{Name: "--tag", Value: "c"},
+				},
+				name: "--tag",
+			},
+			want: rets{
+				values: []string{"a", "b", "c"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.values, got.err = test.args.objs.StringSlice(test.args.name)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if !reflect.DeepEqual(got.values, test.want.values) {
+				t.Errorf("want values %v, got values %v", test.want.values, got.values)
+			}
+		})
+	}
+}
+
+func TestObjects_StringMap(t *testing.T) {
+	type args struct {
+		objs mojo.Objects
+		name string
+	}
+
+	type rets struct {
+		values map[string]string
+		err    error
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want rets
+	}{
+		{
+			name: "ErrInvalidValue",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--label", Value: "a=1,b"},
+				},
+				name: "--label",
+			},
+			want: rets{
+				err: fmt.Errorf("mojo: invalid value: --label"),
+			},
+		},
+		{
+			name: "Many",
+			args: args{
+				objs: []mojo.Object{
+					mojo.CommandObject{Name: "tldr"},
+					mojo.FlagObject{Name: "--label", Value: "a=1,b=2"},
+					mojo.FlagObject{Name: "--label", Value: "a=3,c="},
+				},
+				name: "--label",
+			},
+			want: rets{
+				values: map[string]string{"a": "3", "b": "2", "c": ""},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got rets
+			got.values, got.err = test.args.objs.StringMap(test.args.name)
+			if fmt.Sprintf("%v", got.err) != fmt.Sprintf("%v", test.want.err) {
+				t.Errorf("want err %v, got err %v", test.want.err, got.err)
+				return
+			}
+			if !reflect.DeepEqual(got.values, test.want.values) {
+				t.Errorf("want values %v, got values %v", test.want.values, got.values)
+			}
+		})
+	}
+}